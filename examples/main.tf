@@ -86,12 +86,9 @@ EOF
 }
 
 resource "tinkerbell_workflow" "foo" {
-  template  = tinkerbell_template.foo.id
-  hardwares = <<EOF
-{"device_1":"ff:ff:ff:ff:ff:ff"}
-EOF
+  template = tinkerbell_template.foo.id
 
-  depends_on = [
-    tinkerbell_hardware.foo,
-  ]
+  hardware_map = {
+    device_1 = tinkerbell_hardware.foo.id
+  }
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"log"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 
 	"github.com/tinkerbell/terraform-provider-tinkerbell/tinkerbell"
@@ -10,4 +12,10 @@ func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: tinkerbell.Provider,
 	})
+
+	// Serve returns once Terraform is done with the plugin, so close all
+	// connections opened by the provider before exiting.
+	if err := tinkerbell.CloseConnections(); err != nil {
+		log.Printf("[WARN] %v", err)
+	}
 }
@@ -0,0 +1,419 @@
+package tinkerbell
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	readinessProbeTimeout = 10 * time.Second
+)
+
+// connectionConfig holds all settings required to establish a gRPC connection
+// to the Tink server. It is built from the provider configuration, so each
+// provider instance (including aliased ones) dials with its own settings.
+type connectionConfig struct {
+	grpcAuthority string
+
+	// CA certificate sources. Only one of them may be set.
+	certURL    string
+	caCertPEM  string
+	caCertFile string
+
+	// Client certificate for mutual TLS.
+	clientCertPEM string
+	clientKeyPEM  string
+
+	serverNameOverride string
+	insecureSkipVerify bool
+
+	// insecure disables transport security entirely.
+	insecure bool
+
+	// Bearer token sent with every request. Token file takes precedence.
+	authToken     string
+	authTokenFile string
+
+	// requestTimeout is applied to every request. 0 means no timeout.
+	requestTimeout time.Duration
+
+	// keepaliveTime is an interval of keepalive pings. 0 disables keepalive.
+	keepaliveTime    time.Duration
+	keepaliveTimeout time.Duration
+
+	// Maximum message sizes in bytes. 0 means gRPC defaults.
+	maxRecvMsgSize int
+	maxSendMsgSize int
+
+	// Optional proxy and SSH bastion used to reach the Tink server.
+	proxyURL   string
+	sshBastion *sshBastionConfig
+}
+
+func (cc *connectionConfig) validate() error {
+	if cc.grpcAuthority == "" {
+		return fmt.Errorf("%q must be set", "grpc_authority")
+	}
+
+	if (cc.clientCertPEM == "") != (cc.clientKeyPEM == "") {
+		return fmt.Errorf("%q and %q must be set together", "client_cert_pem", "client_key_pem")
+	}
+
+	if cc.insecure {
+		if cc.caCertPEM != "" || cc.caCertFile != "" || cc.clientCertPEM != "" || cc.insecureSkipVerify {
+			return fmt.Errorf("%q can't be used together with TLS settings", "insecure")
+		}
+
+		return nil
+	}
+
+	if cc.caCertPEM != "" && cc.caCertFile != "" {
+		return fmt.Errorf("only one of %q and %q can be set", "ca_cert_pem", "ca_cert_file")
+	}
+
+	if cc.insecureSkipVerify {
+		if cc.caCertPEM != "" || cc.caCertFile != "" {
+			return fmt.Errorf("%q can't be used together with CA certificate", "insecure_skip_verify")
+		}
+
+		return nil
+	}
+
+	if cc.certURL == "" && cc.caCertPEM == "" && cc.caCertFile == "" {
+		return fmt.Errorf("one of %q, %q or %q must be set", "cert_url", "ca_cert_pem", "ca_cert_file")
+	}
+
+	return nil
+}
+
+func (cc *connectionConfig) dial(ctx context.Context) (*grpc.ClientConn, error) {
+	if err := cc.validate(); err != nil {
+		return nil, &connectionError{
+			summary: "Invalid Tink server connection settings",
+			err:     err,
+		}
+	}
+
+	if err := cc.resolve(ctx); err != nil {
+		return nil, &connectionError{
+			summary: "Failed to resolve Tink server address",
+			err:     err,
+		}
+	}
+
+	opts, err := cc.dialOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.DialContext(ctx, cc.grpcAuthority, opts...)
+	if err != nil {
+		return nil, &connectionError{
+			summary: "Failed to connect to Tink server",
+			err:     fmt.Errorf("connecting to %q: %w", cc.grpcAuthority, err),
+		}
+	}
+
+	return conn, nil
+}
+
+// dialOptions returns gRPC options for dialing Tink server, covering transport security,
+// interceptors, connection settings, proxying and authentication.
+func (cc *connectionConfig) dialOptions(ctx context.Context) ([]grpc.DialOption, error) {
+	transportOpt, err := cc.transportOption(ctx)
+	if err != nil {
+		return nil, &connectionError{
+			summary: "Failed to configure TLS for Tink server connection",
+			err:     err,
+		}
+	}
+
+	opts := []grpc.DialOption{
+		transportOpt,
+		grpc.WithChainUnaryInterceptor(timeoutUnaryInterceptor(cc.requestTimeout), requestNotSentUnaryInterceptor),
+		grpc.WithChainStreamInterceptor(timeoutStreamInterceptor(cc.requestTimeout)),
+	}
+
+	opts = append(opts, cc.connectionOptions()...)
+
+	if cc.proxyURL != "" || cc.sshBastion != nil {
+		d, err := newDialer(cc.proxyURL, cc.sshBastion)
+		if err != nil {
+			return nil, &connectionError{
+				summary: "Invalid proxy or SSH bastion settings",
+				err:     err,
+			}
+		}
+
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return d.DialContext(ctx, "tcp", addr)
+		}))
+	}
+
+	if cc.authToken != "" || cc.authTokenFile != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(&tokenCredentials{
+			token:                    cc.authToken,
+			tokenFile:                cc.authTokenFile,
+			requireTransportSecurity: !cc.insecure,
+		}))
+	}
+
+	return opts, nil
+}
+
+// connectionOptions returns gRPC options configuring keepalive pings and message size
+// limits.
+func (cc *connectionConfig) connectionOptions() []grpc.DialOption {
+	opts := []grpc.DialOption{}
+
+	if cc.keepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    cc.keepaliveTime,
+			Timeout: cc.keepaliveTimeout,
+		}))
+	}
+
+	callOpts := []grpc.CallOption{}
+
+	if cc.maxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(cc.maxRecvMsgSize))
+	}
+
+	if cc.maxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(cc.maxSendMsgSize))
+	}
+
+	if len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	return opts
+}
+
+// resolve checks if the host from gRPC authority can be resolved, so DNS errors
+// can be reported clearly instead of surfacing as an unavailable server.
+func (cc *connectionConfig) resolve(ctx context.Context) error {
+	// Address is resolved remotely when using proxy or SSH bastion.
+	if cc.proxyURL != "" || cc.sshBastion != nil {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(cc.grpcAuthority)
+	if err != nil || host == "" || net.ParseIP(host) != nil {
+		// Not a plain host:port address, leave resolving to gRPC.
+		return nil
+	}
+
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		return fmt.Errorf("resolving %q: %w", host, err)
+	}
+
+	return nil
+}
+
+// probe checks if the Tink server is ready to serve requests using gRPC health
+// checking protocol. Servers not implementing the health service are considered ready.
+func probe(ctx context.Context, conn *grpc.ClientConn) error {
+	ctx, cancel := context.WithTimeout(ctx, readinessProbeTimeout)
+	defer cancel()
+
+	res, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+
+	//nolint:exhaustive // Other codes mean server is unavailable.
+	switch code := statusCode(err); code {
+	case codes.OK:
+		if s := res.GetStatus(); s != grpc_health_v1.HealthCheckResponse_SERVING {
+			return &connectionError{
+				summary: "Tink server is not ready",
+				err:     fmt.Errorf("server reported status %q", s),
+			}
+		}
+
+		return nil
+	case codes.Unimplemented:
+		return nil
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return &connectionError{
+			summary: "Failed to authenticate to Tink server",
+			err:     err,
+		}
+	default:
+		msg := err.Error()
+
+		switch {
+		case strings.Contains(msg, "no such host"), strings.Contains(msg, "produced zero addresses"):
+			return &connectionError{
+				summary: "Failed to resolve Tink server address",
+				err:     err,
+			}
+		case strings.Contains(msg, "authentication handshake failed"), strings.Contains(msg, "x509"):
+			return &connectionError{
+				summary: "TLS handshake with Tink server failed",
+				err:     err,
+			}
+		default:
+			return &connectionError{
+				summary: "Tink server is unavailable",
+				err:     err,
+			}
+		}
+	}
+}
+
+// connectionError describes failure to connect to the Tink server, with summary
+// suitable for diagnostics.
+type connectionError struct {
+	summary string
+	err     error
+}
+
+func (e *connectionError) Error() string {
+	return fmt.Sprintf("%s: %v", e.summary, e.err)
+}
+
+func (e *connectionError) Unwrap() error {
+	return e.err
+}
+
+func (cc *connectionConfig) transportOption(ctx context.Context) (grpc.DialOption, error) {
+	if cc.insecure {
+		return grpc.WithInsecure(), nil
+	}
+
+	creds, err := cc.transportCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.WithTransportCredentials(creds), nil
+}
+
+func (cc *connectionConfig) transportCredentials(ctx context.Context) (credentials.TransportCredentials, error) {
+	tlsConfig := &tls.Config{
+		ServerName: cc.serverNameOverride,
+		//nolint:gosec // Explicitly requested by the user.
+		InsecureSkipVerify: cc.insecureSkipVerify,
+	}
+
+	if !cc.insecureSkipVerify {
+		cp, err := cc.certPool(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = cp
+	}
+
+	if cc.clientCertPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(cc.clientCertPEM), []byte(cc.clientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("parsing client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func (cc *connectionConfig) certPool(ctx context.Context) (*x509.CertPool, error) {
+	switch {
+	case cc.caCertPEM != "":
+		return certPoolFromPEM([]byte(cc.caCertPEM))
+	case cc.caCertFile != "":
+		certs, err := ioutil.ReadFile(cc.caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate file %q: %w", cc.caCertFile, err)
+		}
+
+		return certPoolFromPEM(certs)
+	default:
+		cp, err := fetchCertPool(ctx, cc.certURL)
+		if err != nil {
+			return nil, fmt.Errorf("fetching certificate from %q: %w", cc.certURL, err)
+		}
+
+		return cp, nil
+	}
+}
+
+func certPoolFromPEM(certs []byte) (*x509.CertPool, error) {
+	cp := x509.NewCertPool()
+	if ok := cp.AppendCertsFromPEM(certs); !ok {
+		return nil, fmt.Errorf("no valid PEM certificates found")
+	}
+
+	return cp, nil
+}
+
+func fetchCertPool(ctx context.Context, url string) (*x509.CertPool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	certs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	return certPoolFromPEM(certs)
+}
+
+// tokenCredentials attaches bearer token to every request. If token is read from
+// the file, the file is read on every request, so rotated tokens are picked up
+// without restarting the provider.
+type tokenCredentials struct {
+	token                    string
+	tokenFile                string
+	requireTransportSecurity bool
+}
+
+func (tc *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token := tc.token
+
+	if tc.tokenFile != "" {
+		b, err := ioutil.ReadFile(tc.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading token file %q: %w", tc.tokenFile, err)
+		}
+
+		token = strings.TrimSpace(string(b))
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf("authentication token is empty")
+	}
+
+	return map[string]string{
+		"authorization": "Bearer " + token,
+	}, nil
+}
+
+func (tc *tokenCredentials) RequireTransportSecurity() bool {
+	return tc.requireTransportSecurity
+}
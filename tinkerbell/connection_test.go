@@ -0,0 +1,464 @@
+package tinkerbell
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinkerbell/tink/protos/template"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+func testCertServer(t *testing.T, body []byte) *httptest.Server {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write(body); err != nil {
+			t.Errorf("Writing response: %v", err)
+		}
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func testCertPEM(t *testing.T) []byte {
+	t.Helper()
+
+	s := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(s.Close)
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: s.Certificate().Raw,
+	})
+}
+
+func TestFetchCertPool(t *testing.T) {
+	t.Parallel()
+
+	s := testCertServer(t, testCertPEM(t))
+
+	if _, err := fetchCertPool(context.Background(), s.URL); err != nil {
+		t.Fatalf("Fetching certificate pool: %v", err)
+	}
+}
+
+func TestFetchCertPool_invalidPEM(t *testing.T) {
+	t.Parallel()
+
+	s := testCertServer(t, []byte("not a certificate"))
+
+	if _, err := fetchCertPool(context.Background(), s.URL); err == nil {
+		t.Fatalf("Fetching invalid certificate should fail")
+	}
+}
+
+func TestConnectionConfigDial(t *testing.T) {
+	t.Parallel()
+
+	s := testCertServer(t, testCertPEM(t))
+
+	cc := &connectionConfig{
+		grpcAuthority: "127.0.0.1:42113",
+		certURL:       s.URL,
+	}
+
+	conn, err := cc.dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dialing: %v", err)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Closing connection: %v", err)
+	}
+}
+
+func TestConnectionConfigDial_requiresAuthority(t *testing.T) {
+	t.Parallel()
+
+	cc := &connectionConfig{
+		certURL: "http://127.0.0.1:42114/cert",
+	}
+
+	if _, err := cc.dial(context.Background()); err == nil {
+		t.Fatalf("Dialing without gRPC authority should fail")
+	}
+}
+
+func TestConnectionConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		cc    connectionConfig
+		valid bool
+	}{
+		"cert_url": {
+			cc:    connectionConfig{grpcAuthority: "foo:42113", certURL: "http://foo:42114/cert"},
+			valid: true,
+		},
+		"ca_cert_pem": {
+			cc:    connectionConfig{grpcAuthority: "foo:42113", caCertPEM: "foo"},
+			valid: true,
+		},
+		"ca_cert_pem_overrides_cert_url": {
+			cc:    connectionConfig{grpcAuthority: "foo:42113", certURL: "http://foo:42114/cert", caCertPEM: "foo"},
+			valid: true,
+		},
+		"no_ca": {
+			cc: connectionConfig{grpcAuthority: "foo:42113"},
+		},
+		"insecure_skip_verify": {
+			cc:    connectionConfig{grpcAuthority: "foo:42113", insecureSkipVerify: true},
+			valid: true,
+		},
+		"insecure_skip_verify_with_ca": {
+			cc: connectionConfig{grpcAuthority: "foo:42113", caCertFile: "foo", insecureSkipVerify: true},
+		},
+		"both_ca_sources": {
+			cc: connectionConfig{grpcAuthority: "foo:42113", caCertPEM: "foo", caCertFile: "foo"},
+		},
+		"insecure": {
+			cc:    connectionConfig{grpcAuthority: "foo:42113", insecure: true},
+			valid: true,
+		},
+		"insecure_ignores_cert_url": {
+			cc:    connectionConfig{grpcAuthority: "foo:42113", certURL: "http://foo:42114/cert", insecure: true},
+			valid: true,
+		},
+		"insecure_with_tls_settings": {
+			cc: connectionConfig{grpcAuthority: "foo:42113", caCertPEM: "foo", insecure: true},
+		},
+		"client_cert_without_key": {
+			cc: connectionConfig{grpcAuthority: "foo:42113", caCertPEM: "foo", clientCertPEM: "foo"},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := c.cc.validate(); (err == nil) != c.valid {
+				t.Fatalf("Expected configuration validity to be %v, got error: %v", c.valid, err)
+			}
+		})
+	}
+}
+
+func TestConnectionConfigTransportCredentials_caCertFile(t *testing.T) {
+	t.Parallel()
+
+	f := filepath.Join(t.TempDir(), "ca.pem")
+	if err := ioutil.WriteFile(f, testCertPEM(t), 0o600); err != nil {
+		t.Fatalf("Writing CA certificate file: %v", err)
+	}
+
+	cc := &connectionConfig{
+		grpcAuthority: "127.0.0.1:42113",
+		caCertFile:    f,
+	}
+
+	if _, err := cc.transportCredentials(context.Background()); err != nil {
+		t.Fatalf("Building transport credentials: %v", err)
+	}
+}
+
+func TestConnectionConfigTransportCredentials_badClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	cc := &connectionConfig{
+		grpcAuthority: "127.0.0.1:42113",
+		caCertPEM:     string(testCertPEM(t)),
+		clientCertPEM: "foo",
+		clientKeyPEM:  "bar",
+	}
+
+	if _, err := cc.transportCredentials(context.Background()); err == nil {
+		t.Fatalf("Building transport credentials with invalid client certificate should fail")
+	}
+}
+
+// newTestGRPCServer starts plaintext gRPC server on random local port with services
+// registered by given function and returns its address.
+func newTestGRPCServer(t *testing.T, register func(*grpc.Server), opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listening: %v", err)
+	}
+
+	s := grpc.NewServer(opts...)
+	register(s)
+
+	go func() {
+		// Server may be stopped before it starts serving, if the test finishes quickly.
+		if err := s.Serve(l); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			t.Errorf("Serving: %v", err)
+		}
+	}()
+
+	t.Cleanup(s.Stop)
+
+	return l.Addr().String()
+}
+
+type testTemplateServer struct {
+	template.UnimplementedTemplateServiceServer
+}
+
+func (*testTemplateServer) GetTemplate(
+	ctx context.Context,
+	req *template.GetRequest,
+) (*template.WorkflowTemplate, error) {
+	return &template.WorkflowTemplate{Id: req.GetId()}, nil
+}
+
+func TestConnectionConfigDial_insecure(t *testing.T) {
+	t.Parallel()
+
+	addr := newTestGRPCServer(t, func(s *grpc.Server) {
+		template.RegisterTemplateServiceServer(s, &testTemplateServer{})
+	})
+
+	cc := &connectionConfig{
+		grpcAuthority: addr,
+		insecure:      true,
+	}
+
+	conn, err := cc.dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dialing: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Errorf("Closing connection: %v", err)
+		}
+	})
+
+	req := &template.GetRequest{
+		GetBy: &template.GetRequest_Id{
+			Id: "foo",
+		},
+	}
+
+	tmpl, err := template.NewTemplateServiceClient(conn).GetTemplate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Getting template over insecure connection: %v", err)
+	}
+
+	if tmpl.GetId() != "foo" {
+		t.Fatalf("Expected template ID %q, got %q", "foo", tmpl.GetId())
+	}
+}
+
+func TestConnectionConfigDial_authTokenFile(t *testing.T) {
+	t.Parallel()
+
+	tokens := make(chan string, 2)
+
+	recordToken := func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		tokens <- strings.Join(md.Get("authorization"), ",")
+
+		return handler(ctx, req)
+	}
+
+	addr := newTestGRPCServer(t, func(s *grpc.Server) {
+		template.RegisterTemplateServiceServer(s, &testTemplateServer{})
+	}, grpc.UnaryInterceptor(recordToken))
+
+	f := filepath.Join(t.TempDir(), "token")
+
+	cc := &connectionConfig{
+		grpcAuthority: addr,
+		insecure:      true,
+		authTokenFile: f,
+	}
+
+	conn, err := cc.dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dialing: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Errorf("Closing connection: %v", err)
+		}
+	})
+
+	c := template.NewTemplateServiceClient(conn)
+
+	for _, token := range []string{"foo", "bar"} {
+		if err := ioutil.WriteFile(f, []byte(token+"\n"), 0o600); err != nil {
+			t.Fatalf("Writing token file: %v", err)
+		}
+
+		if _, err := c.GetTemplate(context.Background(), &template.GetRequest{}); err != nil {
+			t.Fatalf("Getting template: %v", err)
+		}
+
+		if got, expected := <-tokens, "Bearer "+token; got != expected {
+			t.Fatalf("Expected authorization header %q, got %q", expected, got)
+		}
+	}
+}
+
+func testConnectionErrorSummary(t *testing.T, err error, summary string) {
+	t.Helper()
+
+	var ce *connectionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("Expected connection error, got: %v", err)
+	}
+
+	if ce.summary != summary {
+		t.Fatalf("Expected error summary %q, got %q: %v", summary, ce.summary, err)
+	}
+}
+
+func testDialAndProbe(t *testing.T, cc *connectionConfig) error {
+	t.Helper()
+
+	conn, err := cc.dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dialing: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Errorf("Closing connection: %v", err)
+		}
+	})
+
+	return probe(context.Background(), conn)
+}
+
+func TestProbe_noHealthService(t *testing.T) {
+	t.Parallel()
+
+	addr := newTestGRPCServer(t, func(s *grpc.Server) {
+		template.RegisterTemplateServiceServer(s, &testTemplateServer{})
+	})
+
+	if err := testDialAndProbe(t, &connectionConfig{grpcAuthority: addr, insecure: true}); err != nil {
+		t.Fatalf("Probing server without health service should succeed, got: %v", err)
+	}
+}
+
+func TestProbe_notServing(t *testing.T) {
+	t.Parallel()
+
+	addr := newTestGRPCServer(t, func(s *grpc.Server) {
+		hs := health.NewServer()
+		hs.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		grpc_health_v1.RegisterHealthServer(s, hs)
+	})
+
+	err := testDialAndProbe(t, &connectionConfig{grpcAuthority: addr, insecure: true})
+	testConnectionErrorSummary(t, err, "Tink server is not ready")
+}
+
+func TestProbe_unavailable(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listening: %v", err)
+	}
+
+	addr := l.Addr().String()
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("Closing listener: %v", err)
+	}
+
+	err = testDialAndProbe(t, &connectionConfig{grpcAuthority: addr, insecure: true})
+	testConnectionErrorSummary(t, err, "Tink server is unavailable")
+}
+
+func TestProbe_tlsHandshakeFailure(t *testing.T) {
+	t.Parallel()
+
+	addr := newTestGRPCServer(t, func(s *grpc.Server) {
+		template.RegisterTemplateServiceServer(s, &testTemplateServer{})
+	})
+
+	err := testDialAndProbe(t, &connectionConfig{grpcAuthority: addr, caCertPEM: string(testCertPEM(t))})
+	testConnectionErrorSummary(t, err, "TLS handshake with Tink server failed")
+}
+
+func TestConnectionConfigDial_unresolvableHost(t *testing.T) {
+	t.Parallel()
+
+	cc := &connectionConfig{
+		grpcAuthority: "tink.invalid:42113",
+		insecure:      true,
+	}
+
+	_, err := cc.dial(context.Background())
+	testConnectionErrorSummary(t, err, "Failed to resolve Tink server address")
+}
+
+type largeTemplateServer struct {
+	template.UnimplementedTemplateServiceServer
+}
+
+func (*largeTemplateServer) GetTemplate(
+	ctx context.Context,
+	req *template.GetRequest,
+) (*template.WorkflowTemplate, error) {
+	return &template.WorkflowTemplate{Data: strings.Repeat("a", 1024)}, nil
+}
+
+func TestConnectionConfigDial_maxRecvMsgSize(t *testing.T) {
+	t.Parallel()
+
+	addr := newTestGRPCServer(t, func(s *grpc.Server) {
+		template.RegisterTemplateServiceServer(s, &largeTemplateServer{})
+	})
+
+	cc := &connectionConfig{
+		grpcAuthority:  addr,
+		insecure:       true,
+		keepaliveTime:  time.Minute,
+		maxRecvMsgSize: 512,
+	}
+
+	conn, err := cc.dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dialing: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Errorf("Closing connection: %v", err)
+		}
+	})
+
+	_, err = template.NewTemplateServiceClient(conn).GetTemplate(context.Background(), &template.GetRequest{})
+	if code := statusCode(err); code != codes.ResourceExhausted {
+		t.Fatalf("Expected %q error code when receiving too large message, got: %v", codes.ResourceExhausted, err)
+	}
+}
@@ -0,0 +1,133 @@
+package tinkerbell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/pkg"
+	"github.com/tinkerbell/tink/protos/hardware"
+)
+
+func dataSourceHardware() *schema.Resource {
+	s := computedSchema(hardwareStructuredSchema())
+
+	lookup := []string{hardwareIDAttribute, "mac", "ip"}
+
+	s[hardwareIDAttribute] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: lookup,
+		Description:  "ID of the hardware to look up.",
+	}
+
+	s["mac"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ExactlyOneOf: lookup,
+		Description:  "MAC address of the hardware interface to look up the hardware by.",
+	}
+
+	s["ip"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ExactlyOneOf: lookup,
+		Description:  "IP address of the hardware to look up the hardware by.",
+	}
+
+	s[dataAttribute] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "JSON formatted hardware data.",
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceHardwareRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Schema: s,
+	}
+}
+
+// computedSchema returns copy of given schema with all attributes computed, for use
+// in data sources.
+func computedSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	r := map[string]*schema.Schema{}
+
+	for k, v := range s {
+		c := &schema.Schema{
+			Type:        v.Type,
+			Computed:    true,
+			Description: v.Description,
+			Elem:        v.Elem,
+		}
+
+		if e, ok := v.Elem.(*schema.Resource); ok {
+			c.Elem = &schema.Resource{
+				Schema: computedSchema(e.Schema),
+			}
+		}
+
+		r[k] = c
+	}
+
+	return r
+}
+
+func dataSourceHardwareRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("creating Tink client: %w", err))
+	}
+
+	c := tc.hardwareClient
+
+	var (
+		hw     *hardware.Hardware
+		lookup string
+	)
+
+	if err := tc.retry(ctx, func() error {
+		switch {
+		case d.Get("mac").(string) != "":
+			lookup = fmt.Sprintf("MAC %q", d.Get("mac").(string))
+			hw, err = getHardwareByMAC(ctx, c, tc.inventory, d.Get("mac").(string))
+		case d.Get("ip").(string) != "":
+			lookup = fmt.Sprintf("IP %q", d.Get("ip").(string))
+			hw, err = getHardwareByIP(ctx, c, tc.inventory, d.Get("ip").(string))
+		default:
+			lookup = fmt.Sprintf("ID %q", d.Get(hardwareIDAttribute).(string))
+			hw, err = getHardware(ctx, c, tc.inventory, d.Get(hardwareIDAttribute).(string))
+		}
+
+		return err
+	}); err != nil {
+		return diagsFromErr(fmt.Errorf("getting hardware with %s: %w", lookup, err))
+	}
+
+	if hw == nil {
+		return diagsFromErr(fmt.Errorf("hardware with %s does not exist", lookup))
+	}
+
+	b, err := json.Marshal(pkg.HardwareWrapper{Hardware: hw})
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("serializing received hardware entry failed: %w", err))
+	}
+
+	attrs := flattenHardware(hw)
+	attrs[dataAttribute] = string(b)
+
+	d.SetId(hw.GetId())
+
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			return diagsFromErr(fmt.Errorf("setting %q field: %w", k, err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/hardware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testHardwareLookupClient returns hardware client with a single entry, which can be
+// looked up by ID, MAC and IP address.
+func testHardwareLookupClient() hardware.HardwareServiceClient {
+	hw := &hardware.Hardware{
+		Id:       "foo",
+		Metadata: testHardwareMetadata,
+		Network: &hardware.Hardware_Network{
+			Interfaces: []*hardware.Hardware_Network_Interface{
+				{
+					Dhcp: &hardware.Hardware_DHCP{
+						Mac: "ff:ff:ff:ff:ff:ff",
+						Ip:  &hardware.Hardware_DHCP_IP{Address: "192.168.1.5"},
+					},
+				},
+			},
+		},
+	}
+
+	get := func(ctx context.Context, in *hardware.GetRequest, opts ...grpc.CallOption) (*hardware.Hardware, error) {
+		if in.Id == hw.Id || in.Mac == "ff:ff:ff:ff:ff:ff" || in.Ip == "192.168.1.5" {
+			return hw, nil
+		}
+
+		return nil, status.Error(codes.Unknown, "SELECT: "+noRowsError)
+	}
+
+	return &hardware.HardwareServiceClientMock{
+		ByIDFunc:  get,
+		ByMACFunc: get,
+		ByIPFunc:  get,
+	}
+}
+
+// testCheckResourceData checks if attributes of given resource data have expected values.
+func testCheckResourceData(t *testing.T, d *schema.ResourceData, expected map[string]interface{}) {
+	t.Helper()
+
+	for k, v := range expected {
+		if got := d.Get(k); got != v {
+			t.Errorf("Expected %q to be %v, got %v", k, v, got)
+		}
+	}
+}
+
+func TestDataSourceHardwareRead(t *testing.T) {
+	t.Parallel()
+
+	client := testHardwareLookupClient()
+
+	cases := map[string]struct {
+		config map[string]interface{}
+		fail   bool
+	}{
+		"id": {
+			config: map[string]interface{}{"hardware_id": "foo"},
+		},
+		"mac": {
+			config: map[string]interface{}{"mac": "ff:ff:ff:ff:ff:ff"},
+		},
+		"ip": {
+			config: map[string]interface{}{"ip": "192.168.1.5"},
+		},
+		"not_found": {
+			config: map[string]interface{}{"mac": "00:00:00:00:00:00"},
+			fail:   true,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := schema.TestResourceDataRaw(t, dataSourceHardware().Schema, c.config)
+
+			diags := dataSourceHardwareRead(context.Background(), d, testTinkClientConfig(&tinkClient{hardwareClient: client}))
+			if diags.HasError() != c.fail {
+				t.Fatalf("Expected failure to be %v, got: %v", c.fail, diags)
+			}
+
+			if c.fail {
+				return
+			}
+
+			testCheckResourceData(t, d, map[string]interface{}{
+				"hardware_id":                         "foo",
+				"metadata.0.facility.0.facility_code": "ewr1",
+				"metadata.0.state":                    "provisioning",
+				"network.0.interfaces.0.dhcp.0.mac":   "ff:ff:ff:ff:ff:ff",
+			})
+
+			if d.Id() != "foo" {
+				t.Errorf("Expected ID to be %q, got %q", "foo", d.Id())
+			}
+
+			if d.Get("data").(string) == "" {
+				t.Errorf("Expected %q to be set", "data")
+			}
+		})
+	}
+}
+
+func TestAccHardwareDataSource(t *testing.T) {
+	t.Parallel()
+
+	rUUID := newUUID(t)
+	rMAC := newMAC(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHardware(testAccHardwareConfig(rUUID, rMAC), "foo") + fmt.Sprintf(`
+data "tinkerbell_hardware" "foo" {
+	mac = %q
+
+	depends_on = [
+		tinkerbell_hardware.foo,
+	]
+}
+`, rMAC),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tinkerbell_hardware.foo", "hardware_id", rUUID),
+					resource.TestCheckResourceAttr("data.tinkerbell_hardware.foo", "metadata.0.facility.0.facility_code", "ewr1"),
+					resource.TestCheckResourceAttrPair("data.tinkerbell_hardware.foo", "data", "tinkerbell_hardware.foo", "data"),
+				),
+			},
+		},
+	})
+}
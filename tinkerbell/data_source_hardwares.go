@@ -0,0 +1,317 @@
+package tinkerbell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jmespath/go-jmespath"
+	"github.com/tinkerbell/tink/pkg"
+	"github.com/tinkerbell/tink/protos/hardware"
+)
+
+func dataSourceHardwares() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceHardwaresRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Schema: mergeSchemas(hardwareFilterSchema(), hardwaresResultSchema()),
+	}
+}
+
+// hardwareFilterSchema returns attributes selecting hardware entries to include.
+func hardwareFilterSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"facility_code": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Only include hardware in given facility.",
+		},
+		"plan_slug": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Only include hardware with given plan slug.",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Only include hardware with given metadata state.",
+		},
+		"arch": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Only include hardware with an interface of given architecture.",
+		},
+		"allow_workflow": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Only include hardware with an interface allowing or not allowing workflows.",
+		},
+		"query": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: validateJMESPath,
+			Description: "JMESPath expression evaluated against JSON formatted hardware data. " +
+				"Only hardware with truthy result is included.",
+		},
+	}
+}
+
+// hardwaresResultSchema returns attributes describing matching hardware entries.
+func hardwaresResultSchema() map[string]*schema.Schema {
+	record := computedSchema(hardwareStructuredSchema())
+
+	record[dataAttribute] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "JSON formatted hardware data.",
+	}
+
+	return map[string]*schema.Schema{
+		"ids": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "IDs of matching hardware.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"macs": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "MAC addresses of all interfaces of matching hardware.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"hardwares": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Matching hardware entries.",
+			Elem: &schema.Resource{
+				Schema: record,
+			},
+		},
+	}
+}
+
+func validateJMESPath(m interface{}, p cty.Path) diag.Diagnostics {
+	if _, err := jmespath.Compile(m.(string)); err != nil {
+		return diagsFromErr(fmt.Errorf("parsing JMESPath expression: %w", err))
+	}
+
+	return nil
+}
+
+// hardwareFilter selects hardware entries matching all set criteria.
+type hardwareFilter struct {
+	facilityCode  string
+	planSlug      string
+	state         string
+	arch          string
+	allowWorkflow *bool
+	query         *jmespath.JMESPath
+}
+
+func hardwareFilterFromResourceData(d *schema.ResourceData) (*hardwareFilter, error) {
+	f := &hardwareFilter{
+		facilityCode: d.Get("facility_code").(string),
+		planSlug:     d.Get("plan_slug").(string),
+		state:        d.Get("state").(string),
+		arch:         d.Get("arch").(string),
+	}
+
+	//nolint:staticcheck // There is no other way to tell unset boolean from false.
+	if v, ok := d.GetOkExists("allow_workflow"); ok {
+		allow := v.(bool)
+		f.allowWorkflow = &allow
+	}
+
+	if q := d.Get("query").(string); q != "" {
+		query, err := jmespath.Compile(q)
+		if err != nil {
+			return nil, fmt.Errorf("parsing JMESPath expression: %w", err)
+		}
+
+		f.query = query
+	}
+
+	return f, nil
+}
+
+// matches checks if given hardware entry matches the filter. Metadata without the
+// expected structure is treated as empty.
+func (f *hardwareFilter) matches(hw *hardware.Hardware) (bool, error) {
+	md, _ := decodeHardwareMetadata(hw.GetMetadata())
+
+	facility := md.Facility
+	if facility == nil {
+		facility = &hardwareFacility{}
+	}
+
+	switch {
+	case f.facilityCode != "" && facility.FacilityCode != f.facilityCode,
+		f.planSlug != "" && facility.PlanSlug != f.planSlug,
+		f.state != "" && md.State != f.state,
+		f.arch != "" && !hardwareHasInterface(hw, func(i *hardware.Hardware_Network_Interface) bool {
+			return i.GetDhcp().GetArch() == f.arch
+		}),
+		f.allowWorkflow != nil && !hardwareHasInterface(hw, func(i *hardware.Hardware_Network_Interface) bool {
+			return i.GetNetboot().GetAllowWorkflow() == *f.allowWorkflow
+		}):
+		return false, nil
+	}
+
+	if f.query == nil {
+		return true, nil
+	}
+
+	return f.matchesQuery(hw)
+}
+
+func (f *hardwareFilter) matchesQuery(hw *hardware.Hardware) (bool, error) {
+	b, err := json.Marshal(pkg.HardwareWrapper{Hardware: hw})
+	if err != nil {
+		return false, fmt.Errorf("serializing hardware: %w", err)
+	}
+
+	var data interface{}
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return false, fmt.Errorf("decoding hardware: %w", err)
+	}
+
+	r, err := f.query.Search(data)
+	if err != nil {
+		return false, fmt.Errorf("evaluating JMESPath expression: %w", err)
+	}
+
+	return isTruthy(r), nil
+}
+
+// isTruthy checks if given JMESPath result is true according to JMESPath rules, where
+// null, false and empty strings, arrays and objects are false.
+func isTruthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+// hardwareHasInterface checks if any interface of given hardware matches given function.
+func hardwareHasInterface(hw *hardware.Hardware, match func(*hardware.Hardware_Network_Interface) bool) bool {
+	for _, i := range hw.GetNetwork().GetInterfaces() {
+		if match(i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dataSourceHardwaresRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("creating Tink client: %w", err))
+	}
+
+	filter, err := hardwareFilterFromResourceData(d)
+	if err != nil {
+		return diagsFromErr(err)
+	}
+
+	var entries map[string]interface{}
+
+	if err := tc.retry(ctx, func() error {
+		entries, err = tc.inventory.get(ctx, hardwareInventory, func(ctx context.Context) (map[string]interface{}, error) {
+			return listHardware(ctx, tc.hardwareClient)
+		})
+
+		return err
+	}); err != nil {
+		return diagsFromErr(fmt.Errorf("listing hardware: %w", err))
+	}
+
+	attrs, err := filterHardware(filter, entries)
+	if err != nil {
+		return diagsFromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", schema.HashString(fmt.Sprint(attrs["ids"]))))
+
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			return diagsFromErr(fmt.Errorf("setting %q field: %w", k, err))
+		}
+	}
+
+	return nil
+}
+
+// filterHardware returns values of 'ids', 'macs' and 'hardwares' attributes for
+// given hardware inventory entries matching the filter, ordered by ID.
+func filterHardware(filter *hardwareFilter, entries map[string]interface{}) (map[string]interface{}, error) {
+	ids := make([]string, 0, len(entries))
+
+	for id := range entries {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	matched := []interface{}{}
+	macs := []interface{}{}
+	records := []interface{}{}
+
+	for _, id := range ids {
+		hw, _ := entries[id].(*hardware.Hardware)
+
+		ok, err := filter.matches(hw)
+		if err != nil {
+			return nil, fmt.Errorf("filtering hardware %q: %w", id, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		record := flattenHardware(hw)
+
+		b, err := json.Marshal(pkg.HardwareWrapper{Hardware: hw})
+		if err != nil {
+			return nil, fmt.Errorf("serializing hardware %q: %w", id, err)
+		}
+
+		record[dataAttribute] = string(b)
+
+		for _, i := range hw.GetNetwork().GetInterfaces() {
+			if mac := i.GetDhcp().GetMac(); mac != "" {
+				macs = append(macs, mac)
+			}
+		}
+
+		matched = append(matched, id)
+		records = append(records, record)
+	}
+
+	return map[string]interface{}{
+		"ids":       matched,
+		"macs":      macs,
+		"hardwares": records,
+	}, nil
+}
@@ -0,0 +1,191 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/hardware"
+	"google.golang.org/grpc"
+)
+
+const testHardwareEntryMetadata = `{"facility":{"facility_code":%q,"plan_slug":"c2.medium.x86",` +
+	`"plan_version_slug":""},"instance":{"tags":[%q]},"state":%q}`
+
+func testHardwareEntry(id, mac, facility, state, arch string, allowWorkflow bool) *hardware.Hardware {
+	return &hardware.Hardware{
+		Id:       id,
+		Metadata: fmt.Sprintf(testHardwareEntryMetadata, facility, id, state),
+		Network: &hardware.Hardware_Network{
+			Interfaces: []*hardware.Hardware_Network_Interface{
+				{
+					Dhcp:    &hardware.Hardware_DHCP{Mac: mac, Arch: arch},
+					Netboot: &hardware.Hardware_Netboot{AllowWorkflow: allowWorkflow},
+				},
+			},
+		},
+	}
+}
+
+// testHardwareAllClientMock returns hardware client mock listing given entries.
+func testHardwareAllClientMock(entries ...*hardware.Hardware) *hardware.HardwareServiceClientMock {
+	return &hardware.HardwareServiceClientMock{
+		AllFunc: func(
+			ctx context.Context,
+			in *hardware.Empty,
+			opts ...grpc.CallOption,
+		) (hardware.HardwareService_AllClient, error) {
+			return testHardwareAllClient(entries...), nil
+		},
+	}
+}
+
+type testHardwaresFilterCase struct {
+	config   map[string]interface{}
+	expected []interface{}
+}
+
+// testHardwaresFilterCases returns filter configurations with IDs of matching
+// entries listed in TestDataSourceHardwaresRead.
+func testHardwaresFilterCases() map[string]testHardwaresFilterCase {
+	return map[string]testHardwaresFilterCase{
+		"all": {
+			config:   map[string]interface{}{},
+			expected: []interface{}{"a", "b", "c", "d"},
+		},
+		"facility_code": {
+			config:   map[string]interface{}{"facility_code": "ewr1"},
+			expected: []interface{}{"a", "b"},
+		},
+		"plan_slug": {
+			config:   map[string]interface{}{"plan_slug": "c2.medium.x86"},
+			expected: []interface{}{"a", "b", "c"},
+		},
+		"state": {
+			config:   map[string]interface{}{"state": "provisioning"},
+			expected: []interface{}{"a", "c"},
+		},
+		"arch": {
+			config:   map[string]interface{}{"arch": "aarch64"},
+			expected: []interface{}{"c"},
+		},
+		"allow_workflow": {
+			config:   map[string]interface{}{"allow_workflow": true},
+			expected: []interface{}{"a", "c"},
+		},
+		"disallow_workflow": {
+			config:   map[string]interface{}{"allow_workflow": false},
+			expected: []interface{}{"b"},
+		},
+		"query": {
+			config:   map[string]interface{}{"query": "metadata.instance.tags[?@ == 'b']"},
+			expected: []interface{}{"b"},
+		},
+		"combined": {
+			config:   map[string]interface{}{"facility_code": "ewr1", "state": "provisioning"},
+			expected: []interface{}{"a"},
+		},
+		"none": {
+			config:   map[string]interface{}{"facility_code": "ams1"},
+			expected: []interface{}{},
+		},
+	}
+}
+
+func TestDataSourceHardwaresRead(t *testing.T) {
+	t.Parallel()
+
+	entries := []*hardware.Hardware{
+		testHardwareEntry("c", "00:00:00:00:00:03", "sjc1", "provisioning", "aarch64", true),
+		testHardwareEntry("a", "00:00:00:00:00:01", "ewr1", "provisioning", "x86_64", true),
+		testHardwareEntry("b", "00:00:00:00:00:02", "ewr1", "in_use", "x86_64", false),
+		{Id: "d"},
+	}
+
+	client := testHardwareAllClientMock(entries...)
+
+	for name, c := range testHardwaresFilterCases() {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := schema.TestResourceDataRaw(t, dataSourceHardwares().Schema, c.config)
+
+			tc := testTinkClientConfig(&tinkClient{hardwareClient: client})
+
+			if diags := dataSourceHardwaresRead(context.Background(), d, tc); diags.HasError() {
+				t.Fatalf("Reading hardwares: %v", diags)
+			}
+
+			if got := d.Get("ids"); !reflect.DeepEqual(got, c.expected) {
+				t.Fatalf("Expected IDs %v, got %v", c.expected, got)
+			}
+
+			if got := d.Get("hardwares.#"); got != len(c.expected) {
+				t.Fatalf("Expected %d hardware entries, got %v", len(c.expected), got)
+			}
+		})
+	}
+}
+
+func TestDataSourceHardwaresRead_macs(t *testing.T) {
+	t.Parallel()
+
+	client := testHardwareAllClientMock(
+		testHardwareEntry("b", "00:00:00:00:00:02", "ewr1", "provisioning", "x86_64", true),
+		testHardwareEntry("a", "00:00:00:00:00:01", "ewr1", "provisioning", "x86_64", true),
+	)
+
+	d := schema.TestResourceDataRaw(t, dataSourceHardwares().Schema, map[string]interface{}{})
+
+	tc := testTinkClientConfig(&tinkClient{hardwareClient: client})
+
+	if diags := dataSourceHardwaresRead(context.Background(), d, tc); diags.HasError() {
+		t.Fatalf("Reading hardwares: %v", diags)
+	}
+
+	expected := []interface{}{"00:00:00:00:00:01", "00:00:00:00:00:02"}
+
+	if got := d.Get("macs"); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected MACs %v, got %v", expected, got)
+	}
+
+	if got := d.Get("hardwares.1.metadata.0.facility.0.facility_code"); got != "ewr1" {
+		t.Fatalf("Expected facility code of second entry to be %q, got %q", "ewr1", got)
+	}
+}
+
+func TestIsTruthy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    interface{}
+		expected bool
+	}{
+		"null":         {nil, false},
+		"false":        {false, false},
+		"true":         {true, true},
+		"empty_string": {"", false},
+		"string":       {"foo", true},
+		"empty_array":  {[]interface{}{}, false},
+		"array":        {[]interface{}{"foo"}, true},
+		"empty_object": {map[string]interface{}{}, false},
+		"object":       {map[string]interface{}{"foo": "bar"}, true},
+		"zero":         {float64(0), true},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isTruthy(c.value); got != c.expected {
+				t.Fatalf("Expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/template"
+	"github.com/tinkerbell/tink/workflow"
+)
+
+func dataSourceTemplate() *schema.Resource {
+	lookup := []string{"id", "name"}
+
+	return &schema.Resource{
+		ReadContext: dataSourceTemplateRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: lookup,
+				Description:  "ID of the template to look up.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: lookup,
+				Description:  "Name of the template to look up.",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Template content in YAML format.",
+			},
+			"tasks": templateTasksSchema(),
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Template creation time in RFC3339 format.",
+			},
+			"updated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Template last update time in RFC3339 format.",
+			},
+		},
+	}
+}
+
+func dataSourceTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("creating Tink client: %w", err))
+	}
+
+	req := &template.GetRequest{}
+
+	var lookup string
+
+	if name := d.Get("name").(string); name != "" {
+		req.GetBy = &template.GetRequest_Name{Name: name}
+		lookup = fmt.Sprintf("name %q", name)
+	} else {
+		req.GetBy = &template.GetRequest_Id{Id: d.Get("id").(string)}
+		lookup = fmt.Sprintf("ID %q", d.Get("id").(string))
+	}
+
+	var t *template.WorkflowTemplate
+
+	err = tc.retry(ctx, func() error {
+		t, err = tc.templateClient.GetTemplate(ctx, req)
+
+		return err //nolint:wrapcheck
+	})
+
+	switch {
+	case isNotFound(err), err == nil && t.GetId() == "":
+		return diagsFromErr(fmt.Errorf("template with %s does not exist", lookup))
+	case err != nil:
+		return diagsFromErr(fmt.Errorf("getting template with %s: %w", lookup, err))
+	}
+
+	tasks, diags := templateTasks(t)
+
+	d.SetId(t.GetId())
+
+	attrs := map[string]interface{}{
+		"name":       t.GetName(),
+		"content":    t.GetData(),
+		"tasks":      tasks,
+		"created_at": formatTimestamp(t.GetCreatedAt()),
+		"updated_at": formatTimestamp(t.GetUpdatedAt()),
+	}
+
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			return diagsFromErr(fmt.Errorf("setting %q field: %w", k, err))
+		}
+	}
+
+	return diags
+}
+
+// templateTasks returns value of 'tasks' attribute for given template. Templates may use
+// Go templating, which makes them impossible to parse, so parsing errors are reported as
+// a warning with no tasks.
+func templateTasks(t *template.WorkflowTemplate) ([]interface{}, diag.Diagnostics) {
+	w, err := workflow.Parse([]byte(t.GetData()))
+	if err != nil {
+		return []interface{}{}, diag.Diagnostics{
+			{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Parsing template %q failed, tasks are not available", t.GetId()),
+				Detail:   err.Error(),
+			},
+		}
+	}
+
+	return flattenTemplateTasks(w), nil
+}
@@ -0,0 +1,157 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/template"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testGetTemplateClient returns template client mock getting given templates by ID or name.
+func testGetTemplateClient(templates ...*template.WorkflowTemplate) *template.TemplateServiceClientMock {
+	return &template.TemplateServiceClientMock{
+		GetTemplateFunc: func(
+			ctx context.Context,
+			in *template.GetRequest,
+			opts ...grpc.CallOption,
+		) (*template.WorkflowTemplate, error) {
+			for _, t := range templates {
+				if t.Id == in.GetId() || t.Name == in.GetName() {
+					return t, nil
+				}
+			}
+
+			return nil, status.Error(codes.Unknown, "SELECT: "+noRowsError)
+		},
+	}
+}
+
+func TestDataSourceTemplateRead(t *testing.T) {
+	t.Parallel()
+
+	client := testGetTemplateClient(
+		&template.WorkflowTemplate{Id: "foo-id", Name: "foo", Data: testAccTemplateContent(1)},
+		&template.WorkflowTemplate{Id: "bar-id", Name: "bar", Data: "{{- if .device_1 }}"},
+	)
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		expectID string
+		tasks    int
+		warning  bool
+		fail     bool
+	}{
+		"id":         {config: map[string]interface{}{"id": "foo-id"}, expectID: "foo-id", tasks: 1},
+		"name":       {config: map[string]interface{}{"name": "foo"}, expectID: "foo-id", tasks: 1},
+		"unparsable": {config: map[string]interface{}{"name": "bar"}, expectID: "bar-id", warning: true},
+		"not_found":  {config: map[string]interface{}{"name": "baz"}, fail: true},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := schema.TestResourceDataRaw(t, dataSourceTemplate().Schema, c.config)
+
+			diags := dataSourceTemplateRead(context.Background(), d, testTinkClientConfig(&tinkClient{templateClient: client}))
+			if diags.HasError() != c.fail {
+				t.Fatalf("Expected error %v, got diagnostics %v", c.fail, diags)
+			}
+
+			if c.fail {
+				return
+			}
+
+			if got := len(diags) > 0 && diags[0].Severity == diag.Warning; got != c.warning {
+				t.Fatalf("Expected warning %v, got diagnostics %v", c.warning, diags)
+			}
+
+			if d.Id() != c.expectID {
+				t.Fatalf("Expected ID %q, got %q", c.expectID, d.Id())
+			}
+
+			if got := d.Get("tasks.#"); got != c.tasks {
+				t.Fatalf("Expected %d tasks, got %v", c.tasks, got)
+			}
+		})
+	}
+}
+
+func TestDataSourceTemplateRead_tasks(t *testing.T) {
+	t.Parallel()
+
+	client := testGetTemplateClient(&template.WorkflowTemplate{
+		Id:        "foo",
+		Name:      "foo",
+		Data:      testAccTemplateContent(1),
+		CreatedAt: timestamppb.New(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)),
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceTemplate().Schema, map[string]interface{}{"id": "foo"})
+	tc := testTinkClientConfig(&tinkClient{templateClient: client})
+
+	if diags := dataSourceTemplateRead(context.Background(), d, tc); diags.HasError() {
+		t.Fatalf("Reading template: %v", diags)
+	}
+
+	expected := map[string]interface{}{
+		"name":                                      "foo",
+		"created_at":                                "2021-01-02T03:04:05Z",
+		"tasks.0.name":                              "os-installation",
+		"tasks.0.worker":                            "{{.device_1}}",
+		"tasks.0.environment.MIRROR_HOST":           "<MIRROR_HOST_IP>",
+		"tasks.0.actions.#":                         4,
+		"tasks.0.actions.1.name":                    "disk-partition",
+		"tasks.0.actions.1.timeout":                 600,
+		"tasks.0.actions.1.volumes.0":               "/statedir:/statedir",
+		"tasks.0.actions.1.environment.MIRROR_HOST": "<MIRROR_HOST_IP>",
+	}
+
+	for k, v := range expected {
+		if got := d.Get(k); got != v {
+			t.Errorf("Expected %q to be %v, got %v", k, v, got)
+		}
+	}
+}
+
+func TestAccTemplateDataSource(t *testing.T) {
+	t.Parallel()
+
+	name := newUUID(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTemplate(name, testAccTemplateContent(1)) + fmt.Sprintf(`
+data "tinkerbell_template" "foo" {
+	name = tinkerbell_template.a%s.name
+
+	depends_on = [
+		tinkerbell_template.a%s,
+	]
+}
+`, name, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.tinkerbell_template.foo", "id",
+						"tinkerbell_template.a"+name, "id",
+					),
+					resource.TestCheckResourceAttr("data.tinkerbell_template.foo", "tasks.0.actions.#", "4"),
+				),
+			},
+		},
+	})
+}
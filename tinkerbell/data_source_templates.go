@@ -0,0 +1,216 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/template"
+)
+
+func dataSourceTemplates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceTemplatesRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"name_filter": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "*",
+				ValidateDiagFunc: validateGlob,
+				Description:      "Glob pattern matching names of the templates to include.",
+			},
+			"templates": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Matching templates, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"content": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"updated_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateGlob(m interface{}, p cty.Path) diag.Diagnostics {
+	if _, err := compileGlob(m.(string)); err != nil {
+		return diagsFromErr(err)
+	}
+
+	return nil
+}
+
+// compileGlob converts given glob pattern into a regular expression. Unlike with
+// path.Match, '*' matches any sequence of characters including '/', as template names
+// are not paths.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+
+	b.WriteString("^(?s:")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i++; i == len(pattern) {
+				return nil, fmt.Errorf("parsing glob pattern: trailing escape character")
+			}
+
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("parsing glob pattern: unterminated character class")
+			}
+
+			b.WriteString(pattern[i : i+end+2])
+
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString(")$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("parsing glob pattern: %w", err)
+	}
+
+	return re, nil
+}
+
+// matchingTemplates returns templates with names matching given glob pattern, sorted
+// by name and ID.
+func matchingTemplates(entries map[string]interface{}, pattern string) ([]*template.WorkflowTemplate, error) {
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []*template.WorkflowTemplate
+
+	for _, e := range entries {
+		if t, _ := e.(*template.WorkflowTemplate); re.MatchString(t.GetName()) {
+			templates = append(templates, t)
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].GetName() != templates[j].GetName() {
+			return templates[i].GetName() < templates[j].GetName()
+		}
+
+		return templates[i].GetId() < templates[j].GetId()
+	})
+
+	return templates, nil
+}
+
+func dataSourceTemplatesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("creating Tink client: %w", err))
+	}
+
+	var entries map[string]interface{}
+
+	if err := tc.retry(ctx, func() error {
+		entries, err = tc.inventory.get(ctx, templateInventory, func(ctx context.Context) (map[string]interface{}, error) {
+			return listTemplates(ctx, tc.templateClient)
+		})
+
+		return err
+	}); err != nil {
+		return diagsFromErr(fmt.Errorf("listing templates: %w", err))
+	}
+
+	pattern := d.Get("name_filter").(string)
+
+	matched, err := matchingTemplates(entries, pattern)
+	if err != nil {
+		return diagsFromErr(err)
+	}
+
+	templates := make([]interface{}, 0, len(matched))
+
+	for _, t := range matched {
+		content, ok, err := templateContent(ctx, tc, t)
+		if err != nil {
+			return diagsFromErr(err)
+		}
+
+		// Template removed in the meantime.
+		if !ok {
+			continue
+		}
+
+		templates = append(templates, map[string]interface{}{
+			"id":         t.GetId(),
+			"name":       t.GetName(),
+			"content":    content,
+			"created_at": formatTimestamp(t.GetCreatedAt()),
+			"updated_at": formatTimestamp(t.GetUpdatedAt()),
+		})
+	}
+
+	d.SetId(pattern)
+
+	if err := d.Set("templates", templates); err != nil {
+		return diagsFromErr(fmt.Errorf("setting %q field: %w", "templates", err))
+	}
+
+	return nil
+}
+
+// templateContent returns content of given listed template. Tink does not include
+// the content when listing templates, so it's only fetched if the entry lacks it.
+// False is returned if the template has been removed in the meantime.
+func templateContent(ctx context.Context, tc *tinkClient, t *template.WorkflowTemplate) (string, bool, error) {
+	if t.GetData() != "" {
+		return t.GetData(), true, nil
+	}
+
+	full, err := getTemplateByID(ctx, tc, t.GetId())
+	if err != nil {
+		return "", false, err
+	}
+
+	if full == nil {
+		return "", false, nil
+	}
+
+	return full.GetData(), true, nil
+}
@@ -0,0 +1,198 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/template"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDataSourceTemplatesRead(t *testing.T) {
+	t.Parallel()
+
+	templates := []*template.WorkflowTemplate{
+		{Id: "3", Name: "ubuntu-focal", Data: "focal"},
+		{Id: "1", Name: "ubuntu-bionic", Data: "bionic"},
+		{Id: "2", Name: "flatcar", Data: "flatcar"},
+		{Id: "4", Name: "team/ubuntu", Data: "team"},
+	}
+
+	// Listed entries include the content, so templates are not fetched one by one.
+	client := testTemplateClient(templates...)
+
+	cases := map[string]struct {
+		filter   string
+		expected []string
+	}{
+		"all":    {filter: "*", expected: []string{"flatcar", "team/ubuntu", "ubuntu-bionic", "ubuntu-focal"}},
+		"slash":  {filter: "team/*", expected: []string{"team/ubuntu"}},
+		"prefix": {filter: "ubuntu-*", expected: []string{"ubuntu-bionic", "ubuntu-focal"}},
+		"exact":  {filter: "flatcar", expected: []string{"flatcar"}},
+		"none":   {filter: "centos-*"},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := schema.TestResourceDataRaw(t, dataSourceTemplates().Schema, map[string]interface{}{
+				"name_filter": c.filter,
+			})
+
+			tc := testTinkClientConfig(&tinkClient{templateClient: client})
+
+			if diags := dataSourceTemplatesRead(context.Background(), d, tc); diags.HasError() {
+				t.Fatalf("Reading templates: %v", diags)
+			}
+
+			if got := d.Get("templates.#"); got != len(c.expected) {
+				t.Fatalf("Expected %d templates, got %v", len(c.expected), got)
+			}
+
+			for i, n := range c.expected {
+				if got := d.Get(fmt.Sprintf("templates.%d.name", i)); got != n {
+					t.Errorf("Expected template %d to be %q, got %q", i, n, got)
+				}
+
+				if got := d.Get(fmt.Sprintf("templates.%d.content", i)); got == "" {
+					t.Errorf("Expected template %d to have content", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDataSourceTemplatesRead_fetchesContent(t *testing.T) {
+	t.Parallel()
+
+	// Tink lists templates without the content.
+	client := testTemplateClient(
+		&template.WorkflowTemplate{Id: "1", Name: "ubuntu-bionic"},
+		&template.WorkflowTemplate{Id: "2", Name: "flatcar", Data: "flatcar"},
+		&template.WorkflowTemplate{Id: "3", Name: "removed"},
+	)
+
+	var fetched []string
+
+	client.GetTemplateFunc = func(
+		ctx context.Context,
+		in *template.GetRequest,
+		opts ...grpc.CallOption,
+	) (*template.WorkflowTemplate, error) {
+		fetched = append(fetched, in.GetId())
+
+		if in.GetId() == "3" {
+			return nil, status.Error(codes.Unknown, "SELECT: "+noRowsError)
+		}
+
+		return &template.WorkflowTemplate{Id: in.GetId(), Name: "ubuntu-bionic", Data: "bionic"}, nil
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceTemplates().Schema, map[string]interface{}{})
+	tc := testTinkClientConfig(&tinkClient{templateClient: client})
+
+	if diags := dataSourceTemplatesRead(context.Background(), d, tc); diags.HasError() {
+		t.Fatalf("Reading templates: %v", diags)
+	}
+
+	if expected := []string{"3", "1"}; !reflect.DeepEqual(fetched, expected) {
+		t.Fatalf("Expected templates %v to be fetched, got %v", expected, fetched)
+	}
+
+	expected := map[string]interface{}{
+		"templates.#":         2,
+		"templates.0.name":    "flatcar",
+		"templates.0.content": "flatcar",
+		"templates.1.name":    "ubuntu-bionic",
+		"templates.1.content": "bionic",
+	}
+
+	for k, v := range expected {
+		if got := d.Get(k); got != v {
+			t.Errorf("Expected %q to be %v, got %v", k, v, got)
+		}
+	}
+}
+
+func TestCompileGlob(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]map[string]bool{
+		"*":           {"": true, "foo": true, "team/foo": true},
+		"ubuntu-?":    {"ubuntu-a": true, "ubuntu-": false, "ubuntu-ab": false},
+		"ubuntu-[ab]": {"ubuntu-a": true, "ubuntu-c": false},
+		"a.b":         {"a.b": true, "axb": false},
+		`foo\*`:       {"foo*": true, "foobar": false},
+	}
+
+	for pattern, names := range cases {
+		re, err := compileGlob(pattern)
+		if err != nil {
+			t.Fatalf("Compiling glob pattern %q: %v", pattern, err)
+		}
+
+		for name, expected := range names {
+			if got := re.MatchString(name); got != expected {
+				t.Errorf("Expected pattern %q matching %q to be %v, got %v", pattern, name, expected, got)
+			}
+		}
+	}
+
+	for _, pattern := range []string{"ubuntu-[", `foo\`, "[]"} {
+		if _, err := compileGlob(pattern); err == nil {
+			t.Errorf("Compiling malformed glob pattern %q should fail", pattern)
+		}
+	}
+}
+
+func TestValidateGlob(t *testing.T) {
+	t.Parallel()
+
+	if diags := validateGlob("ubuntu-[", nil); !diags.HasError() {
+		t.Fatalf("Validating malformed glob pattern should fail")
+	}
+
+	if diags := validateGlob("ubuntu-*", nil); diags.HasError() {
+		t.Fatalf("Validating glob pattern: %v", diags)
+	}
+}
+
+func TestAccTemplatesDataSource(t *testing.T) {
+	t.Parallel()
+
+	name := newUUID(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTemplate(name, testAccTemplateContent(1)) + fmt.Sprintf(`
+data "tinkerbell_templates" "foo" {
+	name_filter = "%s*"
+
+	depends_on = [
+		tinkerbell_template.a%s,
+	]
+}
+`, name[:8], name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tinkerbell_templates.foo", "templates.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.tinkerbell_templates.foo", "templates.0.id",
+						"tinkerbell_template.a"+name, "id",
+					),
+				),
+			},
+		},
+	})
+}
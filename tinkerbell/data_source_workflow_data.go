@@ -0,0 +1,137 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/workflow"
+)
+
+func dataSourceWorkflowData() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceWorkflowDataRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"workflow_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateNotEmpty,
+				Description:      "ID of the workflow to read data of.",
+			},
+			"version": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateNotNegative,
+				Description:      "Version of the data to read. Defaults to the latest version.",
+			},
+			"data": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON formatted workflow data.",
+			},
+			"metadata": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON formatted metadata of the workflow data, e.g. which action stored it.",
+			},
+		},
+	}
+}
+
+func dataSourceWorkflowDataRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("creating Tink client: %w", err))
+	}
+
+	id := d.Get("workflow_id").(string)
+
+	var wf *workflow.Workflow
+
+	if err := tc.retry(ctx, func() error {
+		wf, err = getWorkflow(ctx, tc.workflowClient, tc.inventory, id)
+
+		return err
+	}); err != nil {
+		return diagsFromErr(fmt.Errorf("getting workflow %q: %w", id, err))
+	}
+
+	if wf == nil {
+		return diagsFromErr(fmt.Errorf("workflow %q does not exist", id))
+	}
+
+	version, err := workflowDataVersion(ctx, tc, id, d.Get("version").(int))
+	if err != nil {
+		return diagsFromErr(err)
+	}
+
+	data, metadata, err := getWorkflowDataWithMetadata(ctx, tc, id, version)
+	if err != nil {
+		return diagsFromErr(err)
+	}
+
+	d.SetId(id)
+
+	attrs := map[string]interface{}{
+		"version":  version,
+		"data":     data,
+		"metadata": metadata,
+	}
+
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			return diagsFromErr(fmt.Errorf("setting %q field: %w", k, err))
+		}
+	}
+
+	return nil
+}
+
+// workflowDataVersion returns given version of the workflow data or the latest version,
+// if given version is 0. Version is pinned, so data and metadata are consistent.
+func workflowDataVersion(ctx context.Context, tc *tinkClient, id string, version int) (int, error) {
+	if version != 0 {
+		return version, nil
+	}
+
+	return getWorkflowDataVersion(ctx, tc, id)
+}
+
+// getWorkflowDataWithMetadata returns given version of the workflow data and its metadata.
+func getWorkflowDataWithMetadata(ctx context.Context, tc *tinkClient, id string, version int) (string, string, error) {
+	c := tc.workflowClient
+
+	req := &workflow.GetWorkflowDataRequest{
+		WorkflowId: id,
+		Version:    int32(version),
+	}
+
+	var data, metadata *workflow.GetWorkflowDataResponse
+
+	if err := tc.retry(ctx, func() error {
+		var err error
+
+		data, err = c.GetWorkflowData(ctx, req)
+
+		return err //nolint:wrapcheck
+	}); err != nil {
+		return "", "", fmt.Errorf("getting workflow %q data: %w", id, err)
+	}
+
+	if err := tc.retry(ctx, func() error {
+		var err error
+
+		metadata, err = c.GetWorkflowMetadata(ctx, req)
+
+		return err //nolint:wrapcheck
+	}); err != nil {
+		return "", "", fmt.Errorf("getting workflow %q data metadata: %w", id, err)
+	}
+
+	return string(data.GetData()), string(metadata.GetData()), nil
+}
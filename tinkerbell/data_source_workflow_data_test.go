@@ -0,0 +1,175 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/workflow"
+	"google.golang.org/grpc"
+)
+
+func testWorkflowDataClient(versions map[int32]string) *workflow.WorkflowServiceClientMock {
+	return &workflow.WorkflowServiceClientMock{
+		ListWorkflowsFunc: func(
+			ctx context.Context,
+			in *workflow.Empty,
+			opts ...grpc.CallOption,
+		) (workflow.WorkflowService_ListWorkflowsClient, error) {
+			return testListWorkflowsClient(&workflow.Workflow{Id: "foo"}), nil
+		},
+		GetWorkflowDataVersionFunc: func(
+			ctx context.Context,
+			in *workflow.GetWorkflowDataRequest,
+			opts ...grpc.CallOption,
+		) (*workflow.GetWorkflowDataResponse, error) {
+			return &workflow.GetWorkflowDataResponse{Version: int32(len(versions))}, nil
+		},
+		GetWorkflowDataFunc: func(
+			ctx context.Context,
+			in *workflow.GetWorkflowDataRequest,
+			opts ...grpc.CallOption,
+		) (*workflow.GetWorkflowDataResponse, error) {
+			return &workflow.GetWorkflowDataResponse{Data: []byte(versions[in.Version])}, nil
+		},
+		GetWorkflowMetadataFunc: func(
+			ctx context.Context,
+			in *workflow.GetWorkflowDataRequest,
+			opts ...grpc.CallOption,
+		) (*workflow.GetWorkflowDataResponse, error) {
+			return &workflow.GetWorkflowDataResponse{Data: []byte(fmt.Sprintf(`{"version":%d}`, in.Version))}, nil
+		},
+	}
+}
+
+func TestDataSourceWorkflowDataRead(t *testing.T) {
+	t.Parallel()
+
+	versions := map[int32]string{
+		1: `{"disk":"/dev/sda"}`,
+		2: `{"host_key":"foo"}`,
+	}
+
+	cases := map[string]struct {
+		version  int
+		expected int32
+	}{
+		"latest": {
+			expected: 2,
+		},
+		"pinned": {
+			version:  1,
+			expected: 1,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := schema.TestResourceDataRaw(t, dataSourceWorkflowData().Schema, map[string]interface{}{
+				"workflow_id": "foo",
+				"version":     c.version,
+			})
+
+			tc := &tinkClient{
+				workflowClient: testWorkflowDataClient(versions),
+			}
+
+			if diags := dataSourceWorkflowDataRead(context.Background(), d, testTinkClientConfig(tc)); diags.HasError() {
+				t.Fatalf("Reading workflow data: %v", diags)
+			}
+
+			if got := d.Get("version"); got != int(c.expected) {
+				t.Errorf("Expected version %d, got %v", c.expected, got)
+			}
+
+			if got := d.Get("data"); got != versions[c.expected] {
+				t.Errorf("Expected data %q, got %q", versions[c.expected], got)
+			}
+
+			if expected := fmt.Sprintf(`{"version":%d}`, c.expected); d.Get("metadata") != expected {
+				t.Errorf("Expected metadata %q, got %q", expected, d.Get("metadata"))
+			}
+		})
+	}
+}
+
+func TestDataSourceWorkflowDataRead_missingWorkflow(t *testing.T) {
+	t.Parallel()
+
+	d := schema.TestResourceDataRaw(t, dataSourceWorkflowData().Schema, map[string]interface{}{
+		"workflow_id": "bar",
+	})
+
+	tc := &tinkClient{
+		workflowClient: testWorkflowDataClient(nil),
+	}
+
+	if diags := dataSourceWorkflowDataRead(context.Background(), d, testTinkClientConfig(tc)); !diags.HasError() {
+		t.Fatalf("Reading data of missing workflow should fail")
+	}
+}
+
+func testAccWorkflowData(name, rMAC, data string) string {
+	return fmt.Sprintf(`
+%s
+
+%s
+
+resource "tinkerbell_workflow" "foo" {
+	template      = tinkerbell_template.a%s.id
+	workflow_data = jsonencode(%s)
+
+	hardware_map = {
+		device_1 = tinkerbell_hardware.foo.id
+	}
+}
+
+data "tinkerbell_workflow_data" "foo" {
+	workflow_id = tinkerbell_workflow.foo.id
+
+	depends_on = [
+		tinkerbell_workflow.foo,
+	]
+}
+`,
+		testAccHardware(testAccHardwareConfig(name, rMAC), "foo"),
+		testAccTemplate(name, testAccTemplateContent(1)),
+		name,
+		data,
+	)
+}
+
+func TestAccWorkflow_workflowData(t *testing.T) {
+	t.Parallel()
+
+	name := newUUID(t)
+	rMAC := newMAC(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkflowData(name, rMAC, `{ disk = "/dev/sda" }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tinkerbell_workflow.foo", "workflow_data_version", "1"),
+					resource.TestCheckResourceAttr("data.tinkerbell_workflow_data.foo", "version", "1"),
+					resource.TestCheckResourceAttr("data.tinkerbell_workflow_data.foo", "data", `{"disk":"/dev/sda"}`),
+				),
+			},
+			{
+				Config: testAccWorkflowData(name, rMAC, `{ disk = "/dev/sdb" }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tinkerbell_workflow.foo", "workflow_data_version", "2"),
+					resource.TestCheckResourceAttr("data.tinkerbell_workflow_data.foo", "data", `{"disk":"/dev/sdb"}`),
+				),
+			},
+		},
+	})
+}
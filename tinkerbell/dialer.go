@@ -0,0 +1,321 @@
+package tinkerbell
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	defaultSSHPort = "22"
+)
+
+// sshBastionConfig holds settings for tunneling connections through an SSH jump host.
+type sshBastionConfig struct {
+	host       string
+	user       string
+	privateKey string
+	knownHosts string
+}
+
+// contextDialer dials network connections respecting given context.
+type contextDialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// newDialer builds dialer used for connecting to the Tink server. Connections are
+// made through the proxy if proxyURL is set and through the SSH bastion if given.
+// If both are set, connection to the SSH bastion is made through the proxy.
+func newDialer(proxyURL string, bastion *sshBastionConfig) (contextDialer, error) {
+	var d contextDialer = &net.Dialer{}
+
+	if proxyURL != "" {
+		pd, err := newProxyDialer(proxyURL, d)
+		if err != nil {
+			return nil, fmt.Errorf("creating proxy dialer: %w", err)
+		}
+
+		d = pd
+	}
+
+	if bastion != nil {
+		bd, err := newSSHBastionDialer(bastion, d)
+		if err != nil {
+			return nil, fmt.Errorf("creating SSH bastion dialer: %w", err)
+		}
+
+		d = bd
+	}
+
+	return d, nil
+}
+
+func newProxyDialer(proxyURL string, forward contextDialer) (contextDialer, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing proxy URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http":
+		return &httpConnectDialer{
+			proxyURL: u,
+			forward:  forward,
+		}, nil
+	case "socks5", "socks5h":
+		d, err := proxy.FromURL(u, &forwardDialer{forward})
+		if err != nil {
+			return nil, fmt.Errorf("creating SOCKS5 dialer: %w", err)
+		}
+
+		cd, ok := d.(contextDialer)
+		if !ok {
+			return nil, fmt.Errorf("SOCKS5 dialer does not support context")
+		}
+
+		return cd, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, expected one of 'http', 'socks5' or 'socks5h'", u.Scheme)
+	}
+}
+
+// forwardDialer adapts contextDialer to the proxy.Dialer interface.
+type forwardDialer struct {
+	contextDialer
+}
+
+func (d *forwardDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// httpConnectDialer tunnels connections through an HTTP proxy using CONNECT method.
+type httpConnectDialer struct {
+	proxyURL *url.URL
+	forward  contextDialer
+}
+
+func (d *httpConnectDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := d.forward.DialContext(ctx, network, d.proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to proxy %q: %w", d.proxyURL.Host, err)
+	}
+
+	if err := d.connect(ctx, conn, addr); err != nil {
+		// Connection is unusable anyway, so error on closing can be ignored.
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (d *httpConnectDialer) connect(ctx context.Context, conn net.Conn, addr string) error {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+
+	if u := d.proxyURL.User; u != nil {
+		p, _ := u.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + p))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("setting connection deadline: %w", err)
+		}
+
+		defer conn.SetDeadline(time.Time{}) //nolint:errcheck
+	}
+
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("sending CONNECT request to proxy: %w", err)
+	}
+
+	// Tink server won't send anything before TLS handshake or gRPC preface is sent
+	// by the client, so no data will be lost in the buffer.
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("reading CONNECT response from proxy: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy responded to CONNECT request with status %q", resp.Status)
+	}
+
+	return nil
+}
+
+// sshBastionDialer tunnels connections through an SSH jump host. SSH connection
+// is established on first use and re-established if it breaks. Established SSH
+// clients are tracked in the registry, so they are closed on plugin shutdown.
+type sshBastionDialer struct {
+	addr     string
+	config   *ssh.ClientConfig
+	forward  contextDialer
+	registry *connectionRegistry
+
+	client *ssh.Client
+	mutex  sync.Mutex
+}
+
+func newSSHBastionDialer(c *sshBastionConfig, forward contextDialer) (*sshBastionDialer, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(c.privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+
+	hostKeyCallback, err := knownHostsCallback(c.knownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("loading known hosts: %w", err)
+	}
+
+	addr := c.host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultSSHPort)
+	}
+
+	return &sshBastionDialer{
+		addr: addr,
+		config: &ssh.ClientConfig{
+			User:            c.user,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+		},
+		forward:  forward,
+		registry: openConnections,
+	}, nil
+}
+
+// knownHostsCallback returns host key callback verifying keys using given known_hosts
+// content or using user's known_hosts file if content is empty.
+func knownHostsCallback(content string) (ssh.HostKeyCallback, error) {
+	if content == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("getting user home directory: %w", err)
+		}
+
+		return knownhosts.New(filepath.Join(home, ".ssh", "known_hosts")) //nolint:wrapcheck
+	}
+
+	// knownhosts package only supports reading from files, so write content to
+	// temporary file, which is read entirely when creating the callback.
+	f, err := ioutil.TempFile("", "tinkerbell-known-hosts")
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary file: %w", err)
+	}
+
+	defer os.Remove(f.Name()) //nolint:errcheck
+
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+
+		return nil, fmt.Errorf("writing temporary file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("closing temporary file: %w", err)
+	}
+
+	return knownhosts.New(f.Name()) //nolint:wrapcheck
+}
+
+func (d *sshBastionDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := d.sshClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.Dial(network, addr)
+	if err == nil {
+		return conn, nil
+	}
+
+	// SSH connection may be broken, so reconnect and try again.
+	d.reset(client)
+
+	if client, err = d.sshClient(ctx); err != nil {
+		return nil, err
+	}
+
+	conn, err = client.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %q through SSH bastion %q: %w", addr, d.addr, err)
+	}
+
+	return conn, nil
+}
+
+func (d *sshBastionDialer) sshClient(ctx context.Context) (*ssh.Client, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.client != nil {
+		return d.client, nil
+	}
+
+	conn, err := d.forward.DialContext(ctx, "tcp", d.addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to SSH bastion %q: %w", d.addr, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf("setting connection deadline: %w", err)
+		}
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, d.addr, d.config)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("establishing SSH connection with bastion %q: %w", d.addr, err)
+	}
+
+	// SSH connection outlives the context, so deadline must be cleared.
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		_ = c.Close()
+
+		return nil, fmt.Errorf("clearing connection deadline: %w", err)
+	}
+
+	d.client = ssh.NewClient(c, chans, reqs)
+	d.registry.addSSHClient(d.client)
+
+	return d.client, nil
+}
+
+func (d *sshBastionDialer) reset(client *ssh.Client) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.client == client {
+		d.registry.removeSSHClient(d.client)
+
+		// Connection is considered broken, so error on closing can be ignored.
+		_ = d.client.Close()
+		d.client = nil
+	}
+}
@@ -0,0 +1,436 @@
+package tinkerbell
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/tinkerbell/tink/protos/template"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"google.golang.org/grpc"
+)
+
+// testListen starts accepting connections on random local port and handles them
+// using given function in separate goroutines.
+func testListen(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listening: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go handle(conn)
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+// pipe copies data between given connections until one of them is closed.
+func pipe(a, b io.ReadWriteCloser) {
+	done := make(chan struct{}, 2)
+
+	cp := func(dst io.Writer, src io.Reader) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+
+	go cp(a, b)
+	go cp(b, a)
+
+	<-done
+
+	_ = a.Close()
+	_ = b.Close()
+}
+
+// testSOCKS5Server starts minimal SOCKS5 server supporting only CONNECT command
+// without authentication.
+func testSOCKS5Server(t *testing.T) string {
+	t.Helper()
+
+	return testListen(t, func(conn net.Conn) {
+		addr, err := socks5Handshake(conn)
+		if err != nil {
+			t.Errorf("SOCKS5 handshake: %v", err)
+			_ = conn.Close()
+
+			return
+		}
+
+		target, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Errorf("Dialing %q: %v", addr, err)
+			_ = conn.Close()
+
+			return
+		}
+
+		// Reply with success and zero bound address.
+		if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+			t.Errorf("Writing SOCKS5 reply: %v", err)
+		}
+
+		pipe(conn, target)
+	})
+}
+
+func socks5Handshake(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", fmt.Errorf("reading greeting: %w", err)
+	}
+
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return "", fmt.Errorf("reading methods: %w", err)
+	}
+
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return "", fmt.Errorf("writing method selection: %w", err)
+	}
+
+	req := make([]byte, 4)
+	if _, err := io.ReadFull(conn, req); err != nil {
+		return "", fmt.Errorf("reading request: %w", err)
+	}
+
+	var host string
+
+	switch req[3] {
+	case 1:
+		ip := make([]byte, net.IPv4len)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", fmt.Errorf("reading IPv4 address: %w", err)
+		}
+
+		host = net.IP(ip).String()
+	case 3:
+		l := make([]byte, 1)
+		if _, err := io.ReadFull(conn, l); err != nil {
+			return "", fmt.Errorf("reading domain length: %w", err)
+		}
+
+		domain := make([]byte, l[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", fmt.Errorf("reading domain: %w", err)
+		}
+
+		host = string(domain)
+	default:
+		return "", fmt.Errorf("unsupported address type %d", req[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", fmt.Errorf("reading port: %w", err)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// testHTTPProxy starts HTTP proxy supporting only CONNECT method.
+func testHTTPProxy(t *testing.T) string {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijacking connection: %v", err)
+			_ = target.Close()
+
+			return
+		}
+
+		if _, err := conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+			t.Errorf("Writing CONNECT response: %v", err)
+		}
+
+		pipe(conn, target)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s.Listener.Addr().String()
+}
+
+func testECDSAKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Generating key: %v", err)
+	}
+
+	return k
+}
+
+// testSSHServer starts SSH server supporting only port forwarding, accepting given
+// client key. It returns the server address and known_hosts line for it.
+func testSSHServer(t *testing.T, clientKey ssh.PublicKey) (string, string) {
+	t.Helper()
+
+	hostKey, err := ssh.NewSignerFromKey(testECDSAKey(t))
+	if err != nil {
+		t.Fatalf("Creating host key signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if c.User() == "tink" && string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+
+			return nil, errors.New("unauthorized")
+		},
+	}
+
+	config.AddHostKey(hostKey)
+
+	addr := testListen(t, func(conn net.Conn) {
+		_, chans, reqs, err := ssh.NewServerConn(conn, config)
+		if err != nil {
+			return
+		}
+
+		go ssh.DiscardRequests(reqs)
+
+		for nc := range chans {
+			go handleSSHChannel(t, nc)
+		}
+	})
+
+	return addr, knownhosts.Line([]string{knownhosts.Normalize(addr)}, hostKey.PublicKey())
+}
+
+func handleSSHChannel(t *testing.T, nc ssh.NewChannel) {
+	if nc.ChannelType() != "direct-tcpip" {
+		_ = nc.Reject(ssh.UnknownChannelType, "unsupported channel type")
+
+		return
+	}
+
+	var payload struct {
+		Host     string
+		Port     uint32
+		OrigHost string
+		OrigPort uint32
+	}
+
+	if err := ssh.Unmarshal(nc.ExtraData(), &payload); err != nil {
+		t.Errorf("Parsing direct-tcpip payload: %v", err)
+		_ = nc.Reject(ssh.ConnectionFailed, "bad payload")
+
+		return
+	}
+
+	target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		_ = nc.Reject(ssh.ConnectionFailed, err.Error())
+
+		return
+	}
+
+	ch, reqs, err := nc.Accept()
+	if err != nil {
+		t.Errorf("Accepting channel: %v", err)
+		_ = target.Close()
+
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	pipe(ch, target)
+}
+
+func testTemplateServerAddr(t *testing.T) string {
+	t.Helper()
+
+	return newTestGRPCServer(t, func(s *grpc.Server) {
+		template.RegisterTemplateServiceServer(s, &testTemplateServer{})
+	})
+}
+
+func testGetTemplateThrough(t *testing.T, cc *connectionConfig) {
+	t.Helper()
+
+	conn, err := cc.dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dialing: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Errorf("Closing connection: %v", err)
+		}
+	})
+
+	req := &template.GetRequest{
+		GetBy: &template.GetRequest_Id{
+			Id: "foo",
+		},
+	}
+
+	if _, err := template.NewTemplateServiceClient(conn).GetTemplate(context.Background(), req); err != nil {
+		t.Fatalf("Getting template: %v", err)
+	}
+}
+
+func TestConnectionConfigDial_socks5Proxy(t *testing.T) {
+	t.Parallel()
+
+	testGetTemplateThrough(t, &connectionConfig{
+		grpcAuthority: testTemplateServerAddr(t),
+		insecure:      true,
+		proxyURL:      "socks5://" + testSOCKS5Server(t),
+	})
+}
+
+func TestConnectionConfigDial_httpProxy(t *testing.T) {
+	t.Parallel()
+
+	testGetTemplateThrough(t, &connectionConfig{
+		grpcAuthority: testTemplateServerAddr(t),
+		insecure:      true,
+		proxyURL:      "http://" + testHTTPProxy(t),
+	})
+}
+
+func testSSHBastion(t *testing.T) *sshBastionConfig {
+	t.Helper()
+
+	clientKey := testECDSAKey(t)
+
+	clientPub, err := ssh.NewPublicKey(&clientKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Creating client public key: %v", err)
+	}
+
+	der, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatalf("Marshaling client key: %v", err)
+	}
+
+	addr, knownHosts := testSSHServer(t, clientPub)
+
+	return &sshBastionConfig{
+		host:       addr,
+		user:       "tink",
+		privateKey: string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})),
+		knownHosts: knownHosts,
+	}
+}
+
+func TestConnectionConfigDial_sshBastion(t *testing.T) {
+	t.Parallel()
+
+	testGetTemplateThrough(t, &connectionConfig{
+		grpcAuthority: testTemplateServerAddr(t),
+		insecure:      true,
+		sshBastion:    testSSHBastion(t),
+	})
+}
+
+func TestConnectionConfigDial_sshBastionThroughProxy(t *testing.T) {
+	t.Parallel()
+
+	testGetTemplateThrough(t, &connectionConfig{
+		grpcAuthority: testTemplateServerAddr(t),
+		insecure:      true,
+		proxyURL:      "socks5://" + testSOCKS5Server(t),
+		sshBastion:    testSSHBastion(t),
+	})
+}
+
+func TestSSHBastionDialer_unknownHostKey(t *testing.T) {
+	t.Parallel()
+
+	b := testSSHBastion(t)
+	b.knownHosts = testSSHBastion(t).knownHosts
+
+	d, err := newSSHBastionDialer(b, &net.Dialer{})
+	if err != nil {
+		t.Fatalf("Creating SSH bastion dialer: %v", err)
+	}
+
+	if _, err := d.DialContext(context.Background(), "tcp", "127.0.0.1:42113"); err == nil {
+		t.Fatalf("Connecting to SSH bastion with unknown host key should fail")
+	}
+}
+
+func TestSSHBastionDialer_registry(t *testing.T) {
+	t.Parallel()
+
+	d, err := newSSHBastionDialer(testSSHBastion(t), &net.Dialer{})
+	if err != nil {
+		t.Fatalf("Creating SSH bastion dialer: %v", err)
+	}
+
+	r := &connectionRegistry{}
+	d.registry = r
+
+	addr := testTemplateServerAddr(t)
+
+	conn, err := d.DialContext(context.Background(), "tcp", addr)
+	if err != nil {
+		t.Fatalf("Dialing through SSH bastion: %v", err)
+	}
+
+	defer conn.Close() //nolint:errcheck
+
+	if len(r.sshClients) != 1 {
+		t.Fatalf("Expected 1 registered SSH client, got %d", len(r.sshClients))
+	}
+
+	client := r.sshClients[0]
+
+	if err := r.closeAll(); err != nil {
+		t.Fatalf("Closing all connections: %v", err)
+	}
+
+	if len(r.sshClients) != 0 {
+		t.Fatalf("Expected no registered SSH clients, got %d", len(r.sshClients))
+	}
+
+	if _, err := client.Dial("tcp", addr); err == nil {
+		t.Fatalf("Expected SSH client to be closed")
+	}
+}
@@ -1,7 +1,17 @@
 package tinkerbell
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func diagsFromErr(err error) diag.Diagnostics {
@@ -11,3 +21,98 @@ func diagsFromErr(err error) diag.Diagnostics {
 
 	return nil
 }
+
+func validateDuration(m interface{}, p cty.Path) diag.Diagnostics {
+	d, err := time.ParseDuration(m.(string))
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("parsing duration: %w", err))
+	}
+
+	if d < 0 {
+		return diagsFromErr(fmt.Errorf("duration must not be negative"))
+	}
+
+	return nil
+}
+
+func validatePositiveDuration(m interface{}, p cty.Path) diag.Diagnostics {
+	d, err := time.ParseDuration(m.(string))
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("parsing duration: %w", err))
+	}
+
+	if d <= 0 {
+		return diagsFromErr(fmt.Errorf("duration must be positive"))
+	}
+
+	return nil
+}
+
+func validateNotNegative(m interface{}, p cty.Path) diag.Diagnostics {
+	if m.(int) < 0 {
+		return diagsFromErr(fmt.Errorf("value must not be negative"))
+	}
+
+	return nil
+}
+
+// noRowsError is returned by Tink server when requested entry does not exist in
+// the database.
+const noRowsError = "sql: no rows in result set"
+
+// isNotFound checks if given error indicates that requested entry does not exist.
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return statusCode(err) == codes.NotFound || strings.Contains(err.Error(), noRowsError)
+}
+
+// statusCode returns gRPC status code of given error. Unlike status.Code, it also
+// handles wrapped errors.
+func statusCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	var se interface {
+		GRPCStatus() *status.Status
+	}
+
+	if errors.As(err, &se) {
+		return se.GRPCStatus().Code()
+	}
+
+	return codes.Unknown
+}
+
+func validateProxyURL(m interface{}, p cty.Path) diag.Diagnostics {
+	u, err := url.Parse(m.(string))
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("parsing URL: %w", err))
+	}
+
+	switch u.Scheme {
+	case "http", "socks5", "socks5h":
+	default:
+		return diagsFromErr(fmt.Errorf("unsupported proxy scheme %q, expected one of 'http', 'socks5' or 'socks5h'",
+			u.Scheme))
+	}
+
+	if u.Host == "" {
+		return diagsFromErr(fmt.Errorf("proxy URL must include host"))
+	}
+
+	return nil
+}
+
+// formatTimestamp formats given timestamp in RFC3339 format or returns empty string
+// if timestamp is not set.
+func formatTimestamp(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+
+	return ts.AsTime().Format(time.RFC3339)
+}
@@ -0,0 +1,179 @@
+package tinkerbell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// timeoutError is returned when request to Tink server exceeds its deadline, either
+// the one set by 'request_timeout' or the one set by the resource timeouts.
+type timeoutError struct {
+	method   string
+	resource string
+	err      error
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("request %q for %s timed out, consider increasing %q or resource timeouts: %v",
+		e.method, e.resource, "request_timeout", e.err)
+}
+
+func (e *timeoutError) Unwrap() error {
+	return e.err
+}
+
+// requestResource describes the resource given request refers to, e.g. 'workflow "foo"'.
+// Kind of the resource is taken from the service name of the method and ID from the
+// request, if it has one. Request may be nil, e.g. for streams.
+func requestResource(method string, req interface{}) string {
+	service := path.Base(path.Dir(method))
+	kind := strings.ToLower(strings.TrimSuffix(service[strings.LastIndex(service, ".")+1:], "Service"))
+
+	var id string
+
+	switch r := req.(type) {
+	case interface{ GetId() string }:
+		id = r.GetId()
+	case interface{ GetWorkflowId() string }:
+		id = r.GetWorkflowId()
+	}
+
+	if id == "" {
+		return kind
+	}
+
+	return fmt.Sprintf("%s %q", kind, id)
+}
+
+// wrapTimeoutError wraps given error with timeoutError if it's caused by exceeded deadline.
+func wrapTimeoutError(method string, req interface{}, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		return &timeoutError{
+			method:   method,
+			resource: requestResource(method, req),
+			err:      err,
+		}
+	}
+
+	return err
+}
+
+// timeoutUnaryInterceptor sets given timeout on every unary request. If timeout is 0,
+// only the deadline set on the parent context applies.
+func timeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+
+			defer cancel()
+		}
+
+		return wrapTimeoutError(method, req, invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// timeoutStreamInterceptor sets given timeout on every streaming request. Timeout
+// covers the whole stream, including receiving all messages.
+func timeoutStreamInterceptor(timeout time.Duration) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		cancel := func() {}
+
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			cancel()
+
+			return nil, wrapTimeoutError(method, nil, err)
+		}
+
+		return &timeoutClientStream{
+			ClientStream: s,
+			method:       method,
+			cancel:       cancel,
+		}, nil
+	}
+}
+
+// timeoutClientStream releases the resources associated with stream deadline once
+// the stream is finished.
+type timeoutClientStream struct {
+	grpc.ClientStream
+	method string
+	cancel context.CancelFunc
+}
+
+func (s *timeoutClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.cancel()
+	}
+
+	return wrapTimeoutError(s.method, nil, err)
+}
+
+// requestNotSentError is returned when unary request fails before a stream to Tink
+// server was established, which means the server has not received the request.
+type requestNotSentError struct {
+	err error
+}
+
+func (e *requestNotSentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestNotSentError) Unwrap() error {
+	return e.err
+}
+
+// requestNotSentUnaryInterceptor wraps errors of unary requests which were not sent to
+// the server with requestNotSentError. gRPC only sets request peer once the stream is
+// established, so Unavailable error without peer means the request was never sent.
+func requestNotSentUnaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	p := &peer.Peer{}
+
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(p))...)
+	if err != nil && p.Addr == nil && status.Code(err) == codes.Unavailable {
+		return &requestNotSentError{err: err}
+	}
+
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,228 @@
+package tinkerbell
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinkerbell/tink/protos/template"
+	"github.com/tinkerbell/tink/protos/workflow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type slowTemplateServer struct {
+	template.UnimplementedTemplateServiceServer
+}
+
+func (*slowTemplateServer) GetTemplate(
+	ctx context.Context,
+	req *template.GetRequest,
+) (*template.WorkflowTemplate, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err() //nolint:wrapcheck
+}
+
+func (*slowTemplateServer) ListTemplates(
+	req *template.ListRequest,
+	s template.TemplateService_ListTemplatesServer,
+) error {
+	<-s.Context().Done()
+
+	return s.Context().Err() //nolint:wrapcheck
+}
+
+func testSlowTemplateClient(t *testing.T, timeout time.Duration) template.TemplateServiceClient {
+	t.Helper()
+
+	addr := newTestGRPCServer(t, func(s *grpc.Server) {
+		template.RegisterTemplateServiceServer(s, &slowTemplateServer{})
+	})
+
+	cc := &connectionConfig{
+		grpcAuthority:  addr,
+		insecure:       true,
+		requestTimeout: timeout,
+	}
+
+	conn, err := cc.dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dialing: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Errorf("Closing connection: %v", err)
+		}
+	})
+
+	return template.NewTemplateServiceClient(conn)
+}
+
+func TestTimeoutUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+
+	c := testSlowTemplateClient(t, 100*time.Millisecond)
+
+	_, err := c.GetTemplate(context.Background(), &template.GetRequest{
+		GetBy: &template.GetRequest_Id{Id: "foo"},
+	})
+
+	var te *timeoutError
+	if !errors.As(err, &te) {
+		t.Fatalf("Expected timeout error, got: %v", err)
+	}
+
+	if !strings.HasSuffix(te.method, "/GetTemplate") {
+		t.Fatalf("Expected timeout error to include method name, got %q", te.method)
+	}
+
+	if expected := `template "foo"`; te.resource != expected {
+		t.Fatalf("Expected timeout error to include resource %q, got %q", expected, te.resource)
+	}
+}
+
+func TestTimeoutUnaryInterceptor_parentDeadline(t *testing.T) {
+	t.Parallel()
+
+	c := testSlowTemplateClient(t, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetTemplate(ctx, &template.GetRequest{})
+
+	var te *timeoutError
+	if !errors.As(err, &te) {
+		t.Fatalf("Expected timeout error, got: %v", err)
+	}
+}
+
+func TestTimeoutStreamInterceptor(t *testing.T) {
+	t.Parallel()
+
+	c := testSlowTemplateClient(t, 100*time.Millisecond)
+
+	list, err := c.ListTemplates(context.Background(), &template.ListRequest{})
+	if err != nil {
+		t.Fatalf("Listing templates: %v", err)
+	}
+
+	_, err = list.Recv()
+
+	var te *timeoutError
+	if !errors.As(err, &te) {
+		t.Fatalf("Expected timeout error, got: %v", err)
+	}
+
+	if !strings.HasSuffix(te.method, "/ListTemplates") {
+		t.Fatalf("Expected timeout error to include method name, got %q", te.method)
+	}
+
+	if expected := "template"; te.resource != expected {
+		t.Fatalf("Expected timeout error to include resource kind %q, got %q", expected, te.resource)
+	}
+}
+
+func TestRequestResource(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		method   string
+		req      interface{}
+		expected string
+	}{
+		"id": {
+			method:   "/github.com.tinkerbell.tink.protos.workflow.WorkflowService/GetWorkflow",
+			req:      &workflow.GetRequest{Id: "foo"},
+			expected: `workflow "foo"`,
+		},
+		"workflow_id": {
+			method:   "/github.com.tinkerbell.tink.protos.workflow.WorkflowService/GetWorkflowData",
+			req:      &workflow.GetWorkflowDataRequest{WorkflowId: "foo"},
+			expected: `workflow "foo"`,
+		},
+		"no_id": {
+			method:   "/github.com.tinkerbell.tink.protos.hardware.HardwareService/All",
+			req:      nil,
+			expected: "hardware",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := requestResource(c.method, c.req); got != c.expected {
+				t.Fatalf("Expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+type unavailableTemplateServer struct {
+	template.UnimplementedTemplateServiceServer
+}
+
+func (*unavailableTemplateServer) GetTemplate(
+	ctx context.Context,
+	req *template.GetRequest,
+) (*template.WorkflowTemplate, error) {
+	return nil, status.Error(codes.Unavailable, "foo") //nolint:wrapcheck
+}
+
+func TestRequestNotSentUnaryInterceptor_sent(t *testing.T) {
+	t.Parallel()
+
+	addr := newTestGRPCServer(t, func(s *grpc.Server) {
+		template.RegisterTemplateServiceServer(s, &unavailableTemplateServer{})
+	})
+
+	cc := &connectionConfig{
+		grpcAuthority: addr,
+		insecure:      true,
+	}
+
+	conn, err := cc.dial(context.Background())
+	if err != nil {
+		t.Fatalf("Dialing: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Errorf("Closing connection: %v", err)
+		}
+	})
+
+	_, err = template.NewTemplateServiceClient(conn).GetTemplate(context.Background(), &template.GetRequest{})
+
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected unavailable error, got: %v", err)
+	}
+
+	var nse *requestNotSentError
+	if errors.As(err, &nse) {
+		t.Fatalf("Error returned by the server should not be marked as not sent")
+	}
+}
+
+func TestRequestNotSentUnaryInterceptor_notSent(t *testing.T) {
+	t.Parallel()
+
+	invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "foo") //nolint:wrapcheck
+	}
+
+	err := requestNotSentUnaryInterceptor(context.Background(), "/foo/Bar", nil, nil, nil, invoker)
+
+	var nse *requestNotSentError
+	if !errors.As(err, &nse) {
+		t.Fatalf("Expected request not sent error, got: %v", err)
+	}
+}
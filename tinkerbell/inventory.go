@@ -0,0 +1,140 @@
+package tinkerbell
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	hardwareInventory = "hardware"
+	templateInventory = "template"
+	workflowInventory = "workflow"
+
+	// Refreshing all resources usually takes much less time, so cached lists are
+	// not reused between separate Terraform operations.
+	defaultInventoryTTL          = 30 * time.Second
+	defaultInventoryFetchTimeout = 5 * time.Minute
+)
+
+// inventory caches lists of entries fetched from Tink server, so refreshing many
+// resources of the same kind requires listing them only once. Concurrent requests
+// for the same kind share a single fetch. Cached lists expire after the TTL and are
+// dropped on every write.
+type inventory struct {
+	lists        map[string]*inventoryList
+	ttl          time.Duration
+	fetchTimeout time.Duration
+	mutex        sync.Mutex
+}
+
+// inventoryList holds entries of a single kind indexed by ID. done is closed once
+// the entries are fetched.
+type inventoryList struct {
+	done    chan struct{}
+	entries map[string]interface{}
+	err     error
+	expires time.Time
+}
+
+// inventoryFetch fetches all entries of a single kind indexed by ID.
+type inventoryFetch func(ctx context.Context) (map[string]interface{}, error)
+
+func newInventory() *inventory {
+	return &inventory{
+		lists:        map[string]*inventoryList{},
+		ttl:          defaultInventoryTTL,
+		fetchTimeout: defaultInventoryFetchTimeout,
+	}
+}
+
+// get returns entries of given kind indexed by ID. If they are not cached yet, they
+// are fetched using given function. Fetch is shared by all callers, so it runs in the
+// background without the context of the caller, which may be cancelled before the
+// others are done, while every caller stops waiting when its own context is done.
+// Failed fetches are not cached. Nil inventory fetches entries on every call.
+func (i *inventory) get(ctx context.Context, kind string, fetch inventoryFetch) (map[string]interface{}, error) {
+	if i == nil {
+		return fetch(ctx)
+	}
+
+	l, fetching := i.list(kind)
+
+	if fetching {
+		go i.fetch(kind, l, fetch)
+	}
+
+	select {
+	case <-l.done:
+		return l.entries, l.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for %s entries: %w", kind, ctx.Err())
+	}
+}
+
+// fetch fills given list using given function and marks it as done.
+func (i *inventory) fetch(kind string, l *inventoryList, fetch inventoryFetch) {
+	ctx, cancel := context.WithTimeout(context.Background(), i.fetchTimeout)
+	defer cancel()
+
+	l.entries, l.err = fetch(ctx)
+	l.expires = time.Now().Add(i.ttl)
+
+	if l.err != nil {
+		i.remove(kind, l)
+	}
+
+	close(l.done)
+}
+
+// list returns cached list of given kind. If there is no valid list, a new one is
+// returned together with true, which means the caller must fetch the entries.
+func (i *inventory) list(kind string) (*inventoryList, bool) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if l, ok := i.lists[kind]; ok && !l.expired() {
+		return l, false
+	}
+
+	l := &inventoryList{
+		done: make(chan struct{}),
+	}
+
+	i.lists[kind] = l
+
+	return l, true
+}
+
+// expired checks if the list was fetched longer than TTL ago. List being fetched is
+// never expired.
+func (l *inventoryList) expired() bool {
+	select {
+	case <-l.done:
+		return time.Now().After(l.expires)
+	default:
+		return false
+	}
+}
+
+func (i *inventory) remove(kind string, l *inventoryList) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.lists[kind] == l {
+		delete(i.lists, kind)
+	}
+}
+
+// invalidate drops all cached entries, so they are fetched again on next use.
+func (i *inventory) invalidate() {
+	if i == nil {
+		return
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.lists = map[string]*inventoryList{}
+}
@@ -0,0 +1,222 @@
+package tinkerbell
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInventoryGet_sharesFetch(t *testing.T) {
+	t.Parallel()
+
+	i := newInventory()
+
+	var calls int32
+
+	release := make(chan struct{})
+
+	fetch := func(ctx context.Context) (map[string]interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+
+		return map[string]interface{}{"foo": "bar"}, nil
+	}
+
+	var wg sync.WaitGroup
+
+	for j := 0; j < 10; j++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			entries, err := i.get(context.Background(), templateInventory, fetch)
+			if err != nil {
+				t.Errorf("Getting entries: %v", err)
+
+				return
+			}
+
+			if entries["foo"] != "bar" {
+				t.Errorf("Unexpected entries: %v", entries)
+			}
+		}()
+	}
+
+	// Give goroutines a chance to wait for the fetch in progress.
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if _, err := i.get(context.Background(), templateInventory, fetch); err != nil {
+		t.Fatalf("Getting cached entries: %v", err)
+	}
+
+	if c := atomic.LoadInt32(&calls); c != 1 {
+		t.Fatalf("Expected entries to be fetched once, got %d fetches", c)
+	}
+}
+
+func TestInventoryGet_errorsNotCached(t *testing.T) {
+	t.Parallel()
+
+	i := newInventory()
+	calls := 0
+
+	fetch := func(ctx context.Context) (map[string]interface{}, error) {
+		calls++
+
+		if calls == 1 {
+			return nil, errors.New("foo")
+		}
+
+		return map[string]interface{}{}, nil
+	}
+
+	if _, err := i.get(context.Background(), hardwareInventory, fetch); err == nil {
+		t.Fatalf("Expected first fetch to fail")
+	}
+
+	if _, err := i.get(context.Background(), hardwareInventory, fetch); err != nil {
+		t.Fatalf("Expected second fetch to succeed, got: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("Expected 2 fetches, got %d", calls)
+	}
+}
+
+func TestInventoryInvalidate(t *testing.T) {
+	t.Parallel()
+
+	i := newInventory()
+	calls := 0
+
+	fetch := func(ctx context.Context) (map[string]interface{}, error) {
+		calls++
+
+		return map[string]interface{}{}, nil
+	}
+
+	for j := 0; j < 2; j++ {
+		if _, err := i.get(context.Background(), workflowInventory, fetch); err != nil {
+			t.Fatalf("Getting entries: %v", err)
+		}
+	}
+
+	i.invalidate()
+
+	if _, err := i.get(context.Background(), workflowInventory, fetch); err != nil {
+		t.Fatalf("Getting entries: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("Expected 2 fetches, got %d", calls)
+	}
+}
+
+func TestInventoryGet_nil(t *testing.T) {
+	t.Parallel()
+
+	var i *inventory
+
+	calls := 0
+
+	fetch := func(ctx context.Context) (map[string]interface{}, error) {
+		calls++
+
+		return map[string]interface{}{}, nil
+	}
+
+	for j := 0; j < 2; j++ {
+		if _, err := i.get(context.Background(), workflowInventory, fetch); err != nil {
+			t.Fatalf("Getting entries: %v", err)
+		}
+	}
+
+	i.invalidate()
+
+	if calls != 2 {
+		t.Fatalf("Expected nil inventory to fetch on every call, got %d fetches", calls)
+	}
+}
+
+func TestInventoryGet_expires(t *testing.T) {
+	t.Parallel()
+
+	i := newInventory()
+	i.ttl = time.Millisecond
+	calls := 0
+
+	fetch := func(ctx context.Context) (map[string]interface{}, error) {
+		calls++
+
+		return map[string]interface{}{}, nil
+	}
+
+	for j := 0; j < 2; j++ {
+		if _, err := i.get(context.Background(), templateInventory, fetch); err != nil {
+			t.Fatalf("Getting entries: %v", err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if calls != 2 {
+		t.Fatalf("Expected expired entries to be fetched again, got %d fetches", calls)
+	}
+}
+
+func TestInventoryGet_callerCancelled(t *testing.T) {
+	t.Parallel()
+
+	i := newInventory()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	fetch := func(fctx context.Context) (map[string]interface{}, error) {
+		close(started)
+		<-release
+
+		if err := fctx.Err(); err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"foo": "bar"}, nil
+	}
+
+	errs := make(chan error, 1)
+
+	go func() {
+		_, err := i.get(ctx, hardwareInventory, fetch)
+		errs <- err
+	}()
+
+	<-started
+
+	// Second caller waits for the fetch started by the first one.
+	entries := make(chan map[string]interface{}, 1)
+
+	go func() {
+		e, _ := i.get(context.Background(), hardwareInventory, fetch)
+		entries <- e
+	}()
+
+	// Cancelled caller stops waiting, even though it started the fetch.
+	cancel()
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected cancelled caller to get cancellation error, got: %v", err)
+	}
+
+	close(release)
+
+	if e := <-entries; e["foo"] != "bar" {
+		t.Fatalf("Expected fetch to succeed despite first caller being cancelled, got: %v", e)
+	}
+}
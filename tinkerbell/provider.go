@@ -1,39 +1,265 @@
 package tinkerbell
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc"
 
-	"github.com/tinkerbell/tink/client"
 	"github.com/tinkerbell/tink/protos/hardware"
 	"github.com/tinkerbell/tink/protos/template"
 	"github.com/tinkerbell/tink/protos/workflow"
 )
 
+const (
+	defaultRequestTimeout   = "1m"
+	defaultKeepaliveTimeout = "20s"
+	defaultResourceTimeout  = 5 * time.Minute
+)
+
 // Provider returns the Tinkerbell terraform provider.
 func Provider() *schema.Provider {
 	return &schema.Provider{
-		Schema: map[string]*schema.Schema{
-			"grpc_authority": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Equivalent of TINKERBELL_GRPC_AUTHORITY environment variable.",
-			},
-			"cert_url": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Equivalent of TINKERBELL_CERT_URL environment variable.",
-			},
-		},
+		Schema: mergeSchemas(
+			providerConnectionSchema(),
+			providerTLSSchema(),
+			providerRetrySchema(),
+			providerKeepaliveSchema(),
+			providerProxySchema(),
+		),
 		ResourcesMap: map[string]*schema.Resource{
 			"tinkerbell_template": resourceTemplate(),
 			"tinkerbell_workflow": resourceWorkflow(),
 			"tinkerbell_hardware": resourceHardware(),
 		},
-		ConfigureFunc: providerConfigure,
+		DataSourcesMap: map[string]*schema.Resource{
+			"tinkerbell_hardware":      dataSourceHardware(),
+			"tinkerbell_hardwares":     dataSourceHardwares(),
+			"tinkerbell_template":      dataSourceTemplate(),
+			"tinkerbell_templates":     dataSourceTemplates(),
+			"tinkerbell_workflow_data": dataSourceWorkflowData(),
+		},
+		ConfigureContextFunc: providerConfigure,
+	}
+}
+
+// mergeSchemas returns a single schema with attributes of all given schemas.
+func mergeSchemas(schemas ...map[string]*schema.Schema) map[string]*schema.Schema {
+	r := map[string]*schema.Schema{}
+
+	for _, s := range schemas {
+		for k, v := range s {
+			r[k] = v
+		}
+	}
+
+	return r
+}
+
+// providerConnectionSchema returns attributes selecting Tink server and authenticating to it.
+func providerConnectionSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"grpc_authority": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			DefaultFunc: schema.EnvDefaultFunc("TINKERBELL_GRPC_AUTHORITY", nil),
+			Description: "Equivalent of TINKERBELL_GRPC_AUTHORITY environment variable.",
+		},
+		"auth_token": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Sensitive:     true,
+			ConflictsWith: []string{"auth_token_file"},
+			DefaultFunc:   schema.EnvDefaultFunc("TINKERBELL_AUTH_TOKEN", nil),
+			Description:   "Bearer token sent with every request. Equivalent of TINKERBELL_AUTH_TOKEN environment variable.",
+		},
+		"auth_token_file": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{"auth_token"},
+			Description:   "Path to the file with bearer token sent with every request. File is re-read on every request.",
+		},
+		"request_timeout": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          defaultRequestTimeout,
+			ValidateDiagFunc: validateDuration,
+			Description:      "Timeout for every request sent to Tink server, e.g. '30s'. Set to '0s' to disable.",
+		},
+	}
+}
+
+// providerTLSSchema returns attributes configuring TLS of the connection.
+func providerTLSSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cert_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			DefaultFunc: schema.EnvDefaultFunc("TINKERBELL_CERT_URL", nil),
+			Description: "Equivalent of TINKERBELL_CERT_URL environment variable.",
+		},
+		"ca_cert_pem": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{"cert_url", "ca_cert_file", "insecure_skip_verify"},
+			Description:   "PEM encoded CA certificate used to verify Tink server certificate.",
+		},
+		"ca_cert_file": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{"cert_url", "ca_cert_pem", "insecure_skip_verify"},
+			Description:   "Path to the file with PEM encoded CA certificate used to verify Tink server certificate.",
+		},
+		"client_cert_pem": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			RequiredWith: []string{"client_key_pem"},
+			Description:  "PEM encoded client certificate used for mutual TLS authentication.",
+		},
+		"client_key_pem": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			RequiredWith: []string{"client_cert_pem"},
+			Description:  "PEM encoded private key for the client certificate.",
+		},
+		"server_name_override": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Server name used to verify the hostname in Tink server certificate.",
+		},
+		"insecure_skip_verify": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Skip verification of Tink server certificate. Should only be used for testing.",
+		},
+		"insecure": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			ConflictsWith: []string{
+				"ca_cert_pem",
+				"ca_cert_file",
+				"client_cert_pem",
+				"client_key_pem",
+				"server_name_override",
+				"insecure_skip_verify",
+			},
+			DefaultFunc: schema.EnvDefaultFunc("TINKERBELL_INSECURE", false),
+			Description: "Connect to Tink server without TLS. Equivalent of TINKERBELL_INSECURE environment variable.",
+		},
+	}
+}
+
+// providerRetrySchema returns attributes configuring retries of failed requests.
+func providerRetrySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"max_retries": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          defaultMaxRetries,
+			ValidateDiagFunc: validateMaxRetries,
+			Description:      "Maximum number of retries for requests failing with transient errors.",
+		},
+		"retry_backoff_min": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          defaultRetryBackoffMin,
+			ValidateDiagFunc: validateDuration,
+			Description:      "Minimum time to wait before retrying failed request, e.g. '1s'.",
+		},
+		"retry_backoff_max": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          defaultRetryBackoffMax,
+			ValidateDiagFunc: validateDuration,
+			Description:      "Maximum time to wait before retrying failed request, e.g. '30s'.",
+		},
+	}
+}
+
+// providerKeepaliveSchema returns attributes configuring keepalive pings and message size limits.
+func providerKeepaliveSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"keepalive_time": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "0s",
+			ValidateDiagFunc: validateDuration,
+			Description:      "Interval of keepalive pings sent to Tink server, e.g. '1m'. Set to '0s' to disable.",
+		},
+		"keepalive_timeout": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          defaultKeepaliveTimeout,
+			ValidateDiagFunc: validateDuration,
+			Description:      "Time to wait for keepalive ping acknowledgement before closing the connection.",
+		},
+		"max_recv_msg_size": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ValidateDiagFunc: validateNotNegative,
+			Description:      "Maximum size in bytes of a message received from Tink server. Defaults to gRPC default of 4MB.",
+		},
+		"max_send_msg_size": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ValidateDiagFunc: validateNotNegative,
+			Description:      "Maximum size in bytes of a message sent to Tink server. Defaults to gRPC default.",
+		},
+	}
+}
+
+// providerProxySchema returns attributes configuring how the connection to Tink server is tunneled.
+func providerProxySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"proxy_url": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: validateProxyURL,
+			Description:      "URL of HTTP or SOCKS5 proxy used to connect to Tink server, e.g. 'socks5://127.0.0.1:1080'.",
+		},
+		"ssh_bastion": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "SSH jump host used to connect to Tink server.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"host": {
+						Type:             schema.TypeString,
+						Required:         true,
+						ValidateDiagFunc: validateNotEmpty,
+						Description:      "Address of SSH bastion in 'host' or 'host:port' format.",
+					},
+					"user": {
+						Type:             schema.TypeString,
+						Required:         true,
+						ValidateDiagFunc: validateNotEmpty,
+						Description:      "User used to log in to SSH bastion.",
+					},
+					"private_key": {
+						Type:             schema.TypeString,
+						Required:         true,
+						Sensitive:        true,
+						ValidateDiagFunc: validateNotEmpty,
+						Description:      "PEM encoded private key used to log in to SSH bastion.",
+					},
+					"known_hosts": {
+						Type:     schema.TypeString,
+						Optional: true,
+						Description: "Content of known_hosts file used to verify SSH bastion host key. " +
+							"Defaults to user's known_hosts file.",
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -44,9 +270,120 @@ type tinkClientConfig struct {
 }
 
 type tinkClient struct {
+	conn           *grpc.ClientConn
 	templateClient template.TemplateServiceClient
 	workflowClient workflow.WorkflowServiceClient
 	hardwareClient hardware.HardwareServiceClient
+	retryPolicy    *retryPolicy
+	inventory      *inventory
+}
+
+// retry calls given function, retrying it on transient errors according to the
+// configured retry policy.
+func (c *tinkClient) retry(ctx context.Context, f func() error) error {
+	return c.retryPolicy.do(ctx, isRetryable, f)
+}
+
+// write calls given function modifying server state, retrying it only on errors which
+// prove that the request was not processed, and drops cached inventory afterwards, as
+// it may be outdated.
+func (c *tinkClient) write(ctx context.Context, f func() error) error {
+	defer c.inventory.invalidate()
+
+	return c.retryPolicy.do(ctx, isRetryableWrite, f)
+}
+
+// connectionRegistry tracks gRPC connections and SSH bastion clients opened by the
+// provider, so they can be closed when the plugin process exits.
+type connectionRegistry struct {
+	conns      []*grpc.ClientConn
+	sshClients []*ssh.Client
+	mutex      sync.Mutex
+}
+
+//nolint:gochecknoglobals
+var openConnections = &connectionRegistry{}
+
+func (r *connectionRegistry) add(conn *grpc.ClientConn) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.conns = append(r.conns, conn)
+}
+
+func (r *connectionRegistry) close(conn *grpc.ClientConn) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, c := range r.conns {
+		if c == conn {
+			r.conns = append(r.conns[:i], r.conns[i+1:]...)
+
+			break
+		}
+	}
+
+	if err := conn.Close(); err != nil {
+		return fmt.Errorf("closing connection to %q: %w", conn.Target(), err)
+	}
+
+	return nil
+}
+
+func (r *connectionRegistry) addSSHClient(client *ssh.Client) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sshClients = append(r.sshClients, client)
+}
+
+// removeSSHClient stops tracking given SSH client. Client must be closed by the caller.
+func (r *connectionRegistry) removeSSHClient(client *ssh.Client) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, c := range r.sshClients {
+		if c == client {
+			r.sshClients = append(r.sshClients[:i], r.sshClients[i+1:]...)
+
+			break
+		}
+	}
+}
+
+func (r *connectionRegistry) closeAll() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var errs []string
+
+	for _, c := range r.conns {
+		if err := c.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("closing connection to %q: %v", c.Target(), err))
+		}
+	}
+
+	// gRPC connections may be tunneled through SSH bastions, so close them first.
+	for _, c := range r.sshClients {
+		if err := c.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("closing SSH connection to %q: %v", c.RemoteAddr(), err))
+		}
+	}
+
+	r.conns = nil
+	r.sshClients = nil
+
+	if len(errs) > 0 {
+		return fmt.Errorf("closing connections: %s", strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// CloseConnections closes all connections to Tink servers opened by the provider.
+// It should be called when the plugin process is shutting down.
+func CloseConnections() error {
+	return openConnections.closeAll()
 }
 
 func (tc *tinkClientConfig) New() (*tinkClient, error) {
@@ -57,34 +394,152 @@ func (tc *tinkClientConfig) New() (*tinkClient, error) {
 		return tc.client, nil
 	}
 
-	if grpcAuthority := tc.providerConfig.Get("grpc_authority").(string); grpcAuthority != "" {
-		if err := os.Setenv("TINKERBELL_GRPC_AUTHORITY", grpcAuthority); err != nil {
-			return nil, fmt.Errorf("setting TINKERBELL_GRPC_AUTHORITY environment variable: %w", err)
-		}
+	c, err := newTinkClient(context.Background(), tc.providerConfig)
+	if err != nil {
+		return nil, err
 	}
 
-	if certURL := tc.providerConfig.Get("cert_url").(string); certURL != "" {
-		if err := os.Setenv("TINKERBELL_CERT_URL", certURL); err != nil {
-			return nil, fmt.Errorf("setting TINKERBELL_CERT_URL environment variable: %w", err)
-		}
+	tc.client = c
+
+	return tc.client, nil
+}
+
+func newTinkClient(ctx context.Context, d *schema.ResourceData) (*tinkClient, error) {
+	cc, err := connectionConfigFromResourceData(d)
+	if err != nil {
+		return nil, fmt.Errorf("reading provider configuration: %w", err)
 	}
 
-	conn, err := client.GetConnection()
+	rp, err := retryPolicyFromResourceData(d)
+	if err != nil {
+		return nil, fmt.Errorf("reading provider configuration: %w", err)
+	}
+
+	conn, err := cc.dial(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("creating tink client: %w", err)
 	}
 
-	tc.client = &tinkClient{
+	openConnections.add(conn)
+
+	return &tinkClient{
+		conn:           conn,
 		templateClient: template.NewTemplateServiceClient(conn),
 		workflowClient: workflow.NewWorkflowServiceClient(conn),
 		hardwareClient: hardware.NewHardwareServiceClient(conn),
+		retryPolicy:    rp,
+		inventory:      newInventory(),
+	}, nil
+}
+
+func connectionConfigFromResourceData(d *schema.ResourceData) (*connectionConfig, error) {
+	requestTimeout, err := time.ParseDuration(d.Get("request_timeout").(string))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", "request_timeout", err)
 	}
 
-	return tc.client, nil
+	keepaliveTime, err := time.ParseDuration(d.Get("keepalive_time").(string))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", "keepalive_time", err)
+	}
+
+	keepaliveTimeout, err := time.ParseDuration(d.Get("keepalive_timeout").(string))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", "keepalive_timeout", err)
+	}
+
+	return &connectionConfig{
+		grpcAuthority:      d.Get("grpc_authority").(string),
+		certURL:            d.Get("cert_url").(string),
+		caCertPEM:          d.Get("ca_cert_pem").(string),
+		caCertFile:         d.Get("ca_cert_file").(string),
+		clientCertPEM:      d.Get("client_cert_pem").(string),
+		clientKeyPEM:       d.Get("client_key_pem").(string),
+		serverNameOverride: d.Get("server_name_override").(string),
+		insecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+		insecure:           d.Get("insecure").(bool),
+		authToken:          d.Get("auth_token").(string),
+		authTokenFile:      d.Get("auth_token_file").(string),
+		requestTimeout:     requestTimeout,
+		keepaliveTime:      keepaliveTime,
+		keepaliveTimeout:   keepaliveTimeout,
+		maxRecvMsgSize:     d.Get("max_recv_msg_size").(int),
+		maxSendMsgSize:     d.Get("max_send_msg_size").(int),
+		proxyURL:           d.Get("proxy_url").(string),
+		sshBastion:         sshBastionConfigFromResourceData(d),
+	}, nil
+}
+
+func sshBastionConfigFromResourceData(d *schema.ResourceData) *sshBastionConfig {
+	bastions := d.Get("ssh_bastion").([]interface{})
+	if len(bastions) == 0 || bastions[0] == nil {
+		return nil
+	}
+
+	b := bastions[0].(map[string]interface{})
+
+	return &sshBastionConfig{
+		host:       b["host"].(string),
+		user:       b["user"].(string),
+		privateKey: b["private_key"].(string),
+		knownHosts: b["known_hosts"].(string),
+	}
+}
+
+func retryPolicyFromResourceData(d *schema.ResourceData) (*retryPolicy, error) {
+	backoffMin, err := time.ParseDuration(d.Get("retry_backoff_min").(string))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", "retry_backoff_min", err)
+	}
+
+	backoffMax, err := time.ParseDuration(d.Get("retry_backoff_max").(string))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", "retry_backoff_max", err)
+	}
+
+	if backoffMin > backoffMax {
+		return nil, fmt.Errorf("%q must not be greater than %q", "retry_backoff_min", "retry_backoff_max")
+	}
+
+	return &retryPolicy{
+		maxRetries: d.Get("max_retries").(int),
+		backoffMin: backoffMin,
+		backoffMax: backoffMax,
+	}, nil
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	c, err := newTinkClient(ctx, d)
+	if err != nil {
+		return nil, connectionDiags(err)
+	}
+
+	if err := probe(ctx, c.conn); err != nil {
+		// Connection is unusable anyway, so error on closing can be ignored.
+		_ = openConnections.close(c.conn)
+
+		return nil, connectionDiags(err)
+	}
+
 	return &tinkClientConfig{
 		providerConfig: d,
+		client:         c,
 	}, nil
 }
+
+// connectionDiags converts error returned while connecting to the Tink server
+// into diagnostics, using summary describing the failure where possible.
+func connectionDiags(err error) diag.Diagnostics {
+	var ce *connectionError
+	if !errors.As(err, &ce) {
+		return diagsFromErr(err)
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  ce.summary,
+			Detail:   ce.err.Error(),
+		},
+	}
+}
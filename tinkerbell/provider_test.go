@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 )
 
 //nolint:gochecknoglobals
@@ -29,13 +31,66 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestConnectionRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := &connectionRegistry{}
+
+	for i := 0; i < 2; i++ {
+		conn, err := grpc.Dial("127.0.0.1:42113", grpc.WithInsecure())
+		if err != nil {
+			t.Fatalf("Dialing: %v", err)
+		}
+
+		r.add(conn)
+	}
+
+	if err := r.close(r.conns[0]); err != nil {
+		t.Fatalf("Closing single connection: %v", err)
+	}
+
+	if len(r.conns) != 1 {
+		t.Fatalf("Expected 1 open connection, got %d", len(r.conns))
+	}
+
+	c := r.conns[0]
+
+	if err := r.closeAll(); err != nil {
+		t.Fatalf("Closing all connections: %v", err)
+	}
+
+	if s := c.GetState(); s != connectivity.Shutdown {
+		t.Fatalf("Expected connection to be shut down, got state %q", s)
+	}
+
+	if len(r.conns) != 0 {
+		t.Fatalf("Expected no open connections, got %d", len(r.conns))
+	}
+}
+
 // testAccPreCheck validates the necessary test environment variables exist.
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("TINKERBELL_GRPC_AUTHORITY"); v == "" {
 		t.Fatal("TINKERBELL_GRPC_AUTHORITY must be set for acceptance tests")
 	}
 
+	if v := os.Getenv("TINKERBELL_INSECURE"); v == "true" {
+		return
+	}
+
 	if v := os.Getenv("TINKERBELL_CERT_URL"); v == "" {
-		t.Fatal("TINKERBELL_CERT_URL must be set for acceptance tests")
+		t.Fatal("TINKERBELL_CERT_URL or TINKERBELL_INSECURE must be set for acceptance tests")
+	}
+}
+
+// testTinkClientConfig returns provider configuration using given client, for testing
+// resources against mocked services.
+func testTinkClientConfig(c *tinkClient) *tinkClientConfig {
+	if c.retryPolicy == nil {
+		c.retryPolicy = testRetryPolicy()
+	}
+
+	return &tinkClientConfig{
+		client: c,
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"reflect"
 	"strings"
 
@@ -17,6 +18,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/tinkerbell/tink/pkg"
 	"github.com/tinkerbell/tink/protos/hardware"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -29,8 +32,23 @@ func resourceHardware() *schema.Resource {
 		ReadContext:   resourceHardwareRead,
 		DeleteContext: resourceHardwareDelete,
 		UpdateContext: resourceHardwareUpdate,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceHardwareImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		CustomizeDiff: customdiff.All(
-			customdiff.ForceNewIfChange("data", func(ctx context.Context, old, new, meta interface{}) bool {
+			customdiff.ForceNewIfChange(dataAttribute, func(ctx context.Context, old, new, meta interface{}) bool {
+				// Data becomes unknown when structured attributes change, ID changes are
+				// then handled by hardware_id attribute.
+				if new.(string) == "" {
+					return false
+				}
+
 				oldHw := pkg.HardwareWrapper{}
 
 				if err := json.Unmarshal([]byte(old.(string)), &oldHw); err != nil {
@@ -49,18 +67,134 @@ func resourceHardware() *schema.Resource {
 
 				return oldHw.Hardware.Id != newHw.Hardware.Id
 			}),
+			resourceHardwareCustomizeDiff,
 		),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceHardwareV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceHardwareStateUpgradeV0,
+			},
+		},
+		Schema: resourceHardwareSchema(),
+	}
+}
+
+func resourceHardwareSchema() map[string]*schema.Schema {
+	s := hardwareStructuredSchema()
+
+	s[dataAttribute] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Computed:         true,
+		ConflictsWith:    []string{hardwareIDAttribute, hardwareMetadataAttribute, hardwareNetworkAttribute},
+		ExactlyOneOf:     []string{dataAttribute, hardwareIDAttribute},
+		DiffSuppressFunc: suppressEquivalentJSONDiffs,
+		ValidateDiagFunc: validateHardwareData,
+		Description:      "JSON formatted hardware data. Legacy alternative to the structured attributes.",
+	}
+
+	return s
+}
+
+// resourceHardwareV0 returns schema of the resource before structured attributes were
+// introduced, when only JSON formatted data was supported.
+func resourceHardwareV0() *schema.Resource {
+	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			dataAttribute: {
-				Type:             schema.TypeString,
-				Required:         true,
-				DiffSuppressFunc: suppressEquivalentJSONDiffs,
-				ValidateDiagFunc: validateHardwareData,
+				Type:     schema.TypeString,
+				Required: true,
 			},
 		},
 	}
 }
 
+// resourceHardwareStateUpgradeV0 fills structured attributes using JSON formatted data.
+func resourceHardwareStateUpgradeV0(
+	ctx context.Context,
+	rawState map[string]interface{},
+	meta interface{},
+) (map[string]interface{}, error) {
+	data, _ := rawState[dataAttribute].(string)
+	if data == "" {
+		return rawState, nil
+	}
+
+	hw := pkg.HardwareWrapper{}
+
+	if err := json.Unmarshal([]byte(data), &hw); err != nil {
+		return nil, fmt.Errorf("decoding %q from state: %w", dataAttribute, err)
+	}
+
+	for k, v := range flattenHardware(hw.Hardware) {
+		rawState[k] = v
+	}
+
+	return rawState, nil
+}
+
+// hardwareNetworkChanged checks if network attribute changed, ignoring letter case of
+// MAC addresses, which Tink server stores in lowercase.
+func hardwareNetworkChanged(d *schema.ResourceDiff) bool {
+	if !d.NewValueKnown(hardwareNetworkAttribute) {
+		return true
+	}
+
+	o, n := d.GetChange(hardwareNetworkAttribute)
+	oldNetwork, newNetwork := expandHardwareNetwork(o), expandHardwareNetwork(n)
+
+	for _, network := range []*hardware.Hardware_Network{oldNetwork, newNetwork} {
+		for _, i := range network.GetInterfaces() {
+			if i.GetDhcp() != nil {
+				i.Dhcp.Mac = strings.ToLower(i.Dhcp.Mac)
+			}
+		}
+	}
+
+	return !proto.Equal(oldNetwork, newNetwork)
+}
+
+// resourceHardwareCustomizeDiff keeps data and structured attributes consistent, as only
+// one of them is set in the configuration and the other one must follow.
+func resourceHardwareCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange(dataAttribute) {
+		hw := pkg.HardwareWrapper{}
+
+		if err := json.Unmarshal([]byte(d.Get(dataAttribute).(string)), &hw); err != nil || hw.Hardware == nil {
+			// Data may not be known yet, validation takes care of malformed values.
+			return nil
+		}
+
+		if err := d.SetNew(hardwareIDAttribute, hw.Hardware.Id); err != nil {
+			return fmt.Errorf("setting %q: %w", hardwareIDAttribute, err)
+		}
+
+		for _, k := range []string{hardwareMetadataAttribute, hardwareNetworkAttribute} {
+			if err := d.SetNewComputed(k); err != nil {
+				return fmt.Errorf("marking %q as computed: %w", k, err)
+			}
+		}
+
+		return nil
+	}
+
+	for _, k := range []string{hardwareIDAttribute, hardwareMetadataAttribute, hardwareNetworkAttribute} {
+		if !d.HasChange(k) || (k == hardwareNetworkAttribute && !hardwareNetworkChanged(d)) {
+			continue
+		}
+
+		if err := d.SetNewComputed(dataAttribute); err != nil {
+			return fmt.Errorf("marking %q as computed: %w", dataAttribute, err)
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
 func suppressEquivalentJSONDiffs(k, old, new string, d *schema.ResourceData) bool {
 	ob := bytes.NewBufferString("")
 	if err := json.Compact(ob, []byte(old)); err != nil {
@@ -103,23 +237,6 @@ func validateHardwareData(m interface{}, p cty.Path) diag.Diagnostics {
 	return nil
 }
 
-const (
-	serializationError = "could not serialize access due to read/write dependencies among transactions"
-)
-
-func retryOnTransientError(f func() error) error {
-	err := f()
-	if err == nil {
-		return nil
-	}
-
-	if strings.HasSuffix(err.Error(), serializationError) {
-		return retryOnTransientError(f)
-	}
-
-	return err
-}
-
 func resourceHardwareCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	tc, err := m.(*tinkClientConfig).New()
 	if err != nil {
@@ -128,27 +245,53 @@ func resourceHardwareCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 	c := tc.hardwareClient
 
-	hw := pkg.HardwareWrapper{}
+	hw, err := hardwareFromResourceData(d, "")
+	if err != nil {
+		return diagsFromErr(err)
+	}
 
-	// We can skip error checking here, validate function should already validate it.
-	_ = json.Unmarshal([]byte(d.Get(dataAttribute).(string)), &hw)
+	var h *hardware.Hardware
 
-	h, err := getHardware(ctx, c, hw.Hardware.Id)
-	if err != nil {
-		return diagsFromErr(fmt.Errorf("checking if hardware ID %q already exists: %w", hw.Hardware.Id, err))
+	if err := tc.retry(ctx, func() error {
+		h, err = getHardware(ctx, c, tc.inventory, hw.Id)
+
+		return err
+	}); err != nil {
+		return diagsFromErr(fmt.Errorf("checking if hardware ID %q already exists: %w", hw.Id, err))
 	}
 
 	if h != nil {
-		return diagsFromErr(fmt.Errorf("hardware ID %q already exists", hw.Hardware.Id))
+		return diagsFromErr(fmt.Errorf("hardware ID %q already exists", hw.Id))
 	}
 
-	if _, err := c.Push(ctx, &hardware.PushRequest{Data: hw.Hardware}); err != nil {
+	if err := tc.write(ctx, func() error {
+		_, err := c.Push(ctx, &hardware.PushRequest{Data: hw})
+
+		return err //nolint:wrapcheck
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("pushing hardware data: %w", err))
 	}
 
-	d.SetId(hw.Hardware.Id)
+	d.SetId(hw.Id)
 
-	return nil
+	return resourceHardwareRead(ctx, d, m)
+}
+
+// hardwareFromResourceData builds hardware entry from JSON formatted data if it's
+// set in the configuration or from the structured attributes otherwise, merged with
+// existing metadata of the entry.
+func hardwareFromResourceData(d *schema.ResourceData, existingMetadata string) (*hardware.Hardware, error) {
+	if data := d.Get(dataAttribute).(string); data != "" && d.HasChange(dataAttribute) {
+		hw := pkg.HardwareWrapper{}
+
+		if err := json.Unmarshal([]byte(data), &hw); err != nil {
+			return nil, fmt.Errorf("decoding %q: %w", dataAttribute, err)
+		}
+
+		return hw.Hardware, nil
+	}
+
+	return expandHardware(d, existingMetadata)
 }
 
 func resourceHardwareUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -159,8 +302,13 @@ func resourceHardwareUpdate(ctx context.Context, d *schema.ResourceData, m inter
 
 	c := tc.hardwareClient
 
-	h, err := getHardware(ctx, c, d.Id())
-	if err != nil {
+	var h *hardware.Hardware
+
+	if err := tc.retry(ctx, func() error {
+		h, err = getHardware(ctx, c, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("checking if hardware ID %q already exists: %w", d.Id(), err))
 	}
 
@@ -168,26 +316,152 @@ func resourceHardwareUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		return diagsFromErr(fmt.Errorf("hardware ID %q does not exist", d.Id()))
 	}
 
-	hw := pkg.HardwareWrapper{}
+	hw, err := hardwareFromResourceData(d, h.GetMetadata())
+	if err != nil {
+		return diagsFromErr(err)
+	}
 
-	// We can skip error checking here, validate function should already validate it.
-	_ = json.Unmarshal([]byte(d.Get(dataAttribute).(string)), &hw)
+	if err := tc.write(ctx, func() error {
+		_, err := c.Push(ctx, &hardware.PushRequest{Data: hw})
 
-	if _, err := c.Push(ctx, &hardware.PushRequest{Data: hw.Hardware}); err != nil {
+		return err //nolint:wrapcheck
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("pushing hardware data: %w", err))
 	}
 
-	d.SetId(hw.Hardware.Id)
+	d.SetId(hw.Id)
 
-	return nil
+	return resourceHardwareRead(ctx, d, m)
 }
 
-func getHardware(ctx context.Context, c hardware.HardwareServiceClient, uuid string) (*hardware.Hardware, error) {
+// getHardware returns hardware entry with given ID or nil if it does not exist.
+func getHardware(
+	ctx context.Context,
+	c hardware.HardwareServiceClient,
+	inv *inventory,
+	uuid string,
+) (*hardware.Hardware, error) {
+	hw, err := c.ByID(ctx, &hardware.GetRequest{Id: uuid})
+
+	switch {
+	case err == nil:
+		// Some Tink server versions return empty entry instead of an error.
+		if hw.GetId() == "" {
+			return nil, nil
+		}
+
+		return hw, nil
+	case isNotFound(err):
+		return nil, nil
+	case statusCode(err) == codes.Unimplemented:
+		return getHardwareFromAll(ctx, c, inv, uuid)
+	default:
+		return nil, fmt.Errorf("getting hardware entry by ID: %w", err)
+	}
+}
+
+// getHardwareFromAll looks up hardware entry in the list of all entries, for servers
+// not supporting ByID method.
+func getHardwareFromAll(
+	ctx context.Context,
+	c hardware.HardwareServiceClient,
+	inv *inventory,
+	uuid string,
+) (*hardware.Hardware, error) {
+	entries, err := inv.get(ctx, hardwareInventory, func(ctx context.Context) (map[string]interface{}, error) {
+		return listHardware(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hw, _ := entries[uuid].(*hardware.Hardware)
+
+	return hw, nil
+}
+
+// getHardwareByMAC returns hardware entry with interface using given MAC address or nil
+// if it does not exist.
+func getHardwareByMAC(
+	ctx context.Context,
+	c hardware.HardwareServiceClient,
+	inv *inventory,
+	mac string,
+) (*hardware.Hardware, error) {
+	// Tink server stores MAC addresses in lowercase and matches them exactly.
+	mac = strings.ToLower(mac)
+
+	return findHardware(ctx, c, inv, func() (*hardware.Hardware, error) {
+		return c.ByMAC(ctx, &hardware.GetRequest{Mac: mac}) //nolint:wrapcheck
+	}, func(i *hardware.Hardware_Network_Interface) bool {
+		return strings.EqualFold(i.GetDhcp().GetMac(), mac)
+	})
+}
+
+// getHardwareByIP returns hardware entry with interface using given IP address or nil
+// if it does not exist.
+func getHardwareByIP(
+	ctx context.Context,
+	c hardware.HardwareServiceClient,
+	inv *inventory,
+	ip string,
+) (*hardware.Hardware, error) {
+	return findHardware(ctx, c, inv, func() (*hardware.Hardware, error) {
+		return c.ByIP(ctx, &hardware.GetRequest{Ip: ip}) //nolint:wrapcheck
+	}, func(i *hardware.Hardware_Network_Interface) bool {
+		return i.GetDhcp().GetIp().GetAddress() == ip
+	})
+}
+
+// findHardware returns hardware entry using given lookup function or nil if it does
+// not exist. For servers not supporting the lookup, entry is searched in the list of
+// all entries using given interface matching function.
+func findHardware(
+	ctx context.Context,
+	c hardware.HardwareServiceClient,
+	inv *inventory,
+	get func() (*hardware.Hardware, error),
+	match func(*hardware.Hardware_Network_Interface) bool,
+) (*hardware.Hardware, error) {
+	hw, err := get()
+
+	switch {
+	case err == nil:
+		if hw.GetId() == "" {
+			return nil, nil
+		}
+
+		return hw, nil
+	case isNotFound(err):
+		return nil, nil
+	case statusCode(err) != codes.Unimplemented:
+		return nil, fmt.Errorf("getting hardware entry: %w", err)
+	}
+
+	entries, err := inv.get(ctx, hardwareInventory, func(ctx context.Context) (map[string]interface{}, error) {
+		return listHardware(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if hw, _ := e.(*hardware.Hardware); hardwareHasInterface(hw, match) {
+			return hw, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func listHardware(ctx context.Context, c hardware.HardwareServiceClient) (map[string]interface{}, error) {
 	list, err := c.All(ctx, &hardware.Empty{})
 	if err != nil {
 		return nil, fmt.Errorf("getting all hardware entries: %w", err)
 	}
 
+	entries := map[string]interface{}{}
+
 	for {
 		hw, err := list.Recv()
 		if err != nil {
@@ -202,12 +476,10 @@ func getHardware(ctx context.Context, c hardware.HardwareServiceClient, uuid str
 			return nil, fmt.Errorf("received empty hardware entry: %w", err)
 		}
 
-		if hw.GetId() == uuid {
-			return hw, nil
-		}
+		entries[hw.GetId()] = hw
 	}
 
-	return nil, nil
+	return entries, nil
 }
 
 func resourceHardwareRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -218,8 +490,13 @@ func resourceHardwareRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	c := tc.hardwareClient
 
-	h, err := getHardware(ctx, c, d.Id())
-	if err != nil {
+	var h *hardware.Hardware
+
+	if err := tc.retry(ctx, func() error {
+		h, err = getHardware(ctx, c, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("checking if hardware %q exists: %w", d.Id(), err))
 	}
 
@@ -238,9 +515,50 @@ func resourceHardwareRead(ctx context.Context, d *schema.ResourceData, m interfa
 		return diagsFromErr(fmt.Errorf("failed setting %q field: %w", dataAttribute, err))
 	}
 
+	for k, v := range flattenHardware(h) {
+		if err := d.Set(k, v); err != nil {
+			return diagsFromErr(fmt.Errorf("failed setting %q field: %w", k, err))
+		}
+	}
+
 	return nil
 }
 
+// resourceHardwareImport imports hardware entry by ID or by MAC address of one of its
+// interfaces.
+func resourceHardwareImport(
+	ctx context.Context,
+	d *schema.ResourceData,
+	m interface{},
+) ([]*schema.ResourceData, error) {
+	if _, err := net.ParseMAC(d.Id()); err != nil {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return nil, fmt.Errorf("creating Tink client: %w", err)
+	}
+
+	var hw *hardware.Hardware
+
+	if err := tc.retry(ctx, func() error {
+		hw, err = getHardwareByMAC(ctx, tc.hardwareClient, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("getting hardware with MAC %q: %w", d.Id(), err)
+	}
+
+	if hw == nil {
+		return nil, fmt.Errorf("hardware with MAC %q does not exist", d.Id())
+	}
+
+	d.SetId(hw.GetId())
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceHardwareDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	tc, err := m.(*tinkClientConfig).New()
 	if err != nil {
@@ -253,7 +571,7 @@ func resourceHardwareDelete(ctx context.Context, d *schema.ResourceData, m inter
 		Id: d.Id(),
 	}
 
-	if err := retryOnTransientError(func() error {
+	if err := tc.write(ctx, func() error {
 		_, err := c.Delete(ctx, &req)
 
 		return err //nolint:wrapcheck
@@ -1,13 +1,21 @@
 package tinkerbell
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
+	"io"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/hardware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // From https://stackoverflow.com/a/21027407/2974814
@@ -90,6 +98,17 @@ func TestAccHardware_create(t *testing.T) {
 			{
 				Config: testAccHardware(testAccHardwareConfig(rUUID, rMAC), "foo"),
 			},
+			{
+				ResourceName:      "tinkerbell_hardware.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "tinkerbell_hardware.foo",
+				ImportState:       true,
+				ImportStateId:     rMAC,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -195,3 +214,261 @@ func TestAccHardware_validateData(t *testing.T) {
 		},
 	})
 }
+
+func testAccHardwareStructured(uuid, mac, name string) string {
+	return fmt.Sprintf(`
+resource "tinkerbell_hardware" "%s" {
+  hardware_id = "%s"
+
+  metadata {
+    facility {
+      facility_code = "ewr1"
+      plan_slug     = "c2.medium.x86"
+    }
+
+    instance = "{}"
+    state    = "provisioning"
+  }
+
+  network {
+    interfaces {
+      dhcp {
+        mac  = "%s"
+        arch = "x86_64"
+
+        ip {
+          address = "192.168.1.5"
+          gateway = "192.168.1.1"
+          netmask = "255.255.255.248"
+        }
+      }
+
+      netboot {
+        allow_pxe      = true
+        allow_workflow = true
+      }
+    }
+  }
+}
+`, name, uuid, mac)
+}
+
+func TestAccHardware_structured(t *testing.T) {
+	t.Parallel()
+
+	rUUID := newUUID(t)
+	rMAC := newMAC(t)
+	nMAC := newMAC(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHardwareStructured(rUUID, rMAC, "foo"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tinkerbell_hardware.foo", "network.0.interfaces.0.dhcp.0.mac", rMAC),
+					resource.TestCheckResourceAttrSet("tinkerbell_hardware.foo", "data"),
+				),
+			},
+			{
+				Config: testAccHardwareStructured(rUUID, nMAC, "foo"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tinkerbell_hardware.foo", "network.0.interfaces.0.dhcp.0.mac", nMAC),
+				),
+			},
+		},
+	})
+}
+
+func TestAccHardware_dataSetsStructuredAttributes(t *testing.T) {
+	t.Parallel()
+
+	rUUID := newUUID(t)
+	rMAC := newMAC(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHardware(testAccHardwareConfig(rUUID, rMAC), "foo"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tinkerbell_hardware.foo", "hardware_id", rUUID),
+					resource.TestCheckResourceAttr("tinkerbell_hardware.foo", "metadata.0.facility.0.facility_code", "ewr1"),
+					resource.TestCheckResourceAttr("tinkerbell_hardware.foo", "network.0.interfaces.0.dhcp.0.mac", rMAC),
+				),
+			},
+			{
+				Config:   testAccHardwareStructured(rUUID, rMAC, "foo"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestResourceHardwareStateUpgradeV0(t *testing.T) {
+	t.Parallel()
+
+	rawState := map[string]interface{}{
+		"id":          "foo",
+		dataAttribute: testAccHardwareConfig("foo", "ff:ff:ff:ff:ff:ff"),
+	}
+
+	got, err := resourceHardwareStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("Upgrading state: %v", err)
+	}
+
+	if got[hardwareIDAttribute] != "foo" {
+		t.Fatalf("Expected %q to be %q, got %v", hardwareIDAttribute, "foo", got[hardwareIDAttribute])
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceHardwareSchema(), got)
+
+	if mac := d.Get("network.0.interfaces.0.dhcp.0.mac"); mac != "ff:ff:ff:ff:ff:ff" {
+		t.Fatalf("Expected MAC address to be upgraded, got %q", mac)
+	}
+
+	if state := d.Get("metadata.0.state"); state != "provisioning" {
+		t.Fatalf("Expected state to be upgraded, got %q", state)
+	}
+}
+
+func TestResourceHardwareStateUpgradeV0_malformedData(t *testing.T) {
+	t.Parallel()
+
+	rawState := map[string]interface{}{
+		"id":          "foo",
+		dataAttribute: "bad json",
+	}
+
+	if _, err := resourceHardwareStateUpgradeV0(context.Background(), rawState, nil); err == nil {
+		t.Fatalf("Upgrading state with malformed data should fail")
+	}
+}
+
+func testHardwareAllClient(entries ...*hardware.Hardware) *hardware.HardwareService_AllClientMock {
+	return &hardware.HardwareService_AllClientMock{
+		RecvFunc: func() (*hardware.Hardware, error) {
+			if len(entries) == 0 {
+				return nil, io.EOF
+			}
+
+			hw := entries[0]
+			entries = entries[1:]
+
+			return hw, nil
+		},
+	}
+}
+
+// testHardwareGetResult returns hardware lookup function mock returning given results.
+func testHardwareGetResult(
+	hw *hardware.Hardware,
+	err error,
+) func(context.Context, *hardware.GetRequest, ...grpc.CallOption) (*hardware.Hardware, error) {
+	return func(ctx context.Context, in *hardware.GetRequest, opts ...grpc.CallOption) (*hardware.Hardware, error) {
+		return hw, err
+	}
+}
+
+func TestGetHardware(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		byID     func(ctx context.Context, in *hardware.GetRequest, opts ...grpc.CallOption) (*hardware.Hardware, error)
+		expectID string
+		fail     bool
+	}{
+		"found":         {byID: testHardwareGetResult(&hardware.Hardware{Id: "foo"}, nil), expectID: "foo"},
+		"not_found":     {byID: testHardwareGetResult(nil, status.Error(codes.NotFound, "foo"))},
+		"no_rows":       {byID: testHardwareGetResult(nil, status.Error(codes.Unknown, "SELECT: "+noRowsError))},
+		"empty_entry":   {byID: testHardwareGetResult(&hardware.Hardware{}, nil)},
+		"unimplemented": {byID: testHardwareGetResult(nil, status.Error(codes.Unimplemented, "foo")), expectID: "foo"},
+		"error":         {byID: testHardwareGetResult(nil, status.Error(codes.Internal, "foo")), fail: true},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := testHardwareAllClientMock(&hardware.Hardware{Id: "bar"}, &hardware.Hardware{Id: "foo"})
+			client.ByIDFunc = c.byID
+
+			hw, err := getHardware(context.Background(), client, nil, "foo")
+			if (err != nil) != c.fail {
+				t.Fatalf("Expected error %v, got: %v", c.fail, err)
+			}
+
+			if got := hw.GetId(); got != c.expectID {
+				t.Fatalf("Expected hardware ID %q, got %q", c.expectID, got)
+			}
+		})
+	}
+}
+
+func TestResourceHardwareImport(t *testing.T) {
+	t.Parallel()
+
+	mac := "ff:ff:ff:ff:ff:ff"
+	hw := testHardwareEntry("foo", mac, "ewr1", "provisioning", "x86_64", true)
+
+	// Tink server matches MAC addresses exactly.
+	byMAC := func(ctx context.Context, in *hardware.GetRequest, opts ...grpc.CallOption) (*hardware.Hardware, error) {
+		if in.Mac != mac {
+			return &hardware.Hardware{}, nil
+		}
+
+		return hw, nil
+	}
+
+	cases := map[string]struct {
+		id       string
+		byMAC    func(ctx context.Context, in *hardware.GetRequest, opts ...grpc.CallOption) (*hardware.Hardware, error)
+		expectID string
+	}{
+		"id":            {id: "foo", expectID: "foo"},
+		"mac":           {id: mac, byMAC: byMAC, expectID: "foo"},
+		"mac_uppercase": {id: strings.ToUpper(mac), byMAC: byMAC, expectID: "foo"},
+		"mac_unimplemented": {
+			id:       "FF:FF:FF:FF:FF:FF",
+			byMAC:    testHardwareGetResult(nil, status.Error(codes.Unimplemented, "foo")),
+			expectID: "foo",
+		},
+		"mac_not_found": {
+			id:    "00:00:00:00:00:00",
+			byMAC: testHardwareGetResult(nil, status.Error(codes.Unknown, "SELECT: "+noRowsError)),
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := testHardwareAllClientMock(&hardware.Hardware{Id: "bar"}, hw)
+			client.ByMACFunc = c.byMAC
+
+			d := schema.TestResourceDataRaw(t, resourceHardware().Schema, map[string]interface{}{})
+			d.SetId(c.id)
+
+			// Import is expected to fail if no hardware ID is expected.
+			r, err := resourceHardwareImport(context.Background(), d, testTinkClientConfig(&tinkClient{hardwareClient: client}))
+			if (err != nil) != (c.expectID == "") {
+				t.Fatalf("Expected hardware %q, got error: %v", c.expectID, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := r[0].Id(); got != c.expectID {
+				t.Fatalf("Expected hardware ID %q, got %q", c.expectID, got)
+			}
+		})
+	}
+}
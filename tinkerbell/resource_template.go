@@ -19,6 +19,15 @@ func resourceTemplate() *schema.Resource {
 		ReadContext:   resourceTemplateRead,
 		DeleteContext: resourceTemplateDelete,
 		UpdateContext: resourceTemplateUpdate,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTemplateImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:             schema.TypeString,
@@ -54,7 +63,58 @@ func validateTemplate(m interface{}, p cty.Path) diag.Diagnostics {
 	return nil
 }
 
-func getTemplate(ctx context.Context, c template.TemplateServiceClient, id string) (*template.WorkflowTemplate, error) {
+// getTemplate returns template with given ID or nil if it does not exist.
+func getTemplate(
+	ctx context.Context,
+	c template.TemplateServiceClient,
+	inv *inventory,
+	id string,
+) (*template.WorkflowTemplate, error) {
+	entries, err := inv.get(ctx, templateInventory, func(ctx context.Context) (map[string]interface{}, error) {
+		return listTemplates(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t, _ := entries[id].(*template.WorkflowTemplate)
+
+	return t, nil
+}
+
+// getTemplateByName returns template with given name or nil if it does not exist.
+func getTemplateByName(
+	ctx context.Context,
+	c template.TemplateServiceClient,
+	inv *inventory,
+	name string,
+) (*template.WorkflowTemplate, error) {
+	entries, err := inv.get(ctx, templateInventory, func(ctx context.Context) (map[string]interface{}, error) {
+		return listTemplates(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var found *template.WorkflowTemplate
+
+	for _, e := range entries {
+		t, _ := e.(*template.WorkflowTemplate)
+		if t.GetName() != name {
+			continue
+		}
+
+		if found != nil {
+			return nil, fmt.Errorf("found multiple templates named %q: %q and %q", name, found.GetId(), t.GetId())
+		}
+
+		found = t
+	}
+
+	return found, nil
+}
+
+func listTemplates(ctx context.Context, c template.TemplateServiceClient) (map[string]interface{}, error) {
 	list, err := c.ListTemplates(ctx, &template.ListRequest{
 		FilterBy: &template.ListRequest_Name{
 			Name: "*",
@@ -64,6 +124,8 @@ func getTemplate(ctx context.Context, c template.TemplateServiceClient, id strin
 		return nil, fmt.Errorf("getting all template entries: %w", err)
 	}
 
+	entries := map[string]interface{}{}
+
 	for {
 		t, err := list.Recv()
 		if err != nil {
@@ -78,12 +140,10 @@ func getTemplate(ctx context.Context, c template.TemplateServiceClient, id strin
 			return nil, fmt.Errorf("received empty template entry: %w", err)
 		}
 
-		if t.GetId() == id {
-			return t, nil
-		}
+		entries[t.GetId()] = t
 	}
 
-	return nil, nil
+	return entries, nil
 }
 
 func resourceTemplateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -99,8 +159,13 @@ func resourceTemplateCreate(ctx context.Context, d *schema.ResourceData, m inter
 		Data: d.Get("content").(string),
 	}
 
-	res, err := c.CreateTemplate(ctx, &req)
-	if err != nil {
+	var res *template.CreateResponse
+
+	if err := tc.write(ctx, func() error {
+		res, err = c.CreateTemplate(ctx, &req)
+
+		return err //nolint:wrapcheck
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("creating template: %w", err))
 	}
 
@@ -117,8 +182,13 @@ func resourceTemplateRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	c := tc.templateClient
 
-	t, err := getTemplate(ctx, c, d.Id())
-	if err != nil {
+	var t *template.WorkflowTemplate
+
+	if err := tc.retry(ctx, func() error {
+		t, err = getTemplate(ctx, c, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("checking if template exists: %w", err))
 	}
 
@@ -134,18 +204,64 @@ func resourceTemplateRead(ctx context.Context, d *schema.ResourceData, m interfa
 		},
 	}
 
-	t, err = c.GetTemplate(ctx, &req)
-	if err != nil {
+	if err := tc.retry(ctx, func() error {
+		t, err = c.GetTemplate(ctx, &req)
+
+		return err //nolint:wrapcheck
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("getting template %q: %w", d.Id(), err))
 	}
 
-	if err := d.Set("content", t.Data); err != nil {
-		return diagsFromErr(fmt.Errorf("setting %q field: %w", "content", err))
+	attrs := map[string]interface{}{
+		"name":    t.GetName(),
+		"content": t.GetData(),
+	}
+
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			return diagsFromErr(fmt.Errorf("setting %q field: %w", k, err))
+		}
 	}
 
 	return nil
 }
 
+// resourceTemplateImport imports template by ID or, if no template has such ID,
+// by name.
+func resourceTemplateImport(
+	ctx context.Context,
+	d *schema.ResourceData,
+	m interface{},
+) ([]*schema.ResourceData, error) {
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return nil, fmt.Errorf("creating Tink client: %w", err)
+	}
+
+	var t *template.WorkflowTemplate
+
+	if err := tc.retry(ctx, func() error {
+		t, err = getTemplate(ctx, tc.templateClient, tc.inventory, d.Id())
+		if err != nil || t != nil {
+			return err
+		}
+
+		t, err = getTemplateByName(ctx, tc.templateClient, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("getting template %q: %w", d.Id(), err)
+	}
+
+	if t == nil {
+		return nil, fmt.Errorf("template with ID or name %q does not exist", d.Id())
+	}
+
+	d.SetId(t.GetId())
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceTemplateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	tc, err := m.(*tinkClientConfig).New()
 	if err != nil {
@@ -154,8 +270,13 @@ func resourceTemplateDelete(ctx context.Context, d *schema.ResourceData, m inter
 
 	c := tc.templateClient
 
-	t, err := getTemplate(ctx, c, d.Id())
-	if err != nil {
+	var t *template.WorkflowTemplate
+
+	if err := tc.retry(ctx, func() error {
+		t, err = getTemplate(ctx, c, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("checking if template exists: %w", err))
 	}
 
@@ -171,7 +292,7 @@ func resourceTemplateDelete(ctx context.Context, d *schema.ResourceData, m inter
 		},
 	}
 
-	if err := retryOnTransientError(func() error {
+	if err := tc.write(ctx, func() error {
 		_, err := c.DeleteTemplate(ctx, &req)
 
 		return err //nolint:wrapcheck
@@ -190,8 +311,13 @@ func resourceTemplateUpdate(ctx context.Context, d *schema.ResourceData, m inter
 
 	c := tc.templateClient
 
-	t, err := getTemplate(ctx, c, d.Id())
-	if err != nil {
+	var t *template.WorkflowTemplate
+
+	if err := tc.retry(ctx, func() error {
+		t, err = getTemplate(ctx, c, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("checking if template exists: %w", err))
 	}
 
@@ -205,7 +331,11 @@ func resourceTemplateUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		Data: d.Get("content").(string),
 	}
 
-	if _, err := c.UpdateTemplate(ctx, &req); err != nil {
+	if err := tc.write(ctx, func() error {
+		_, err := c.UpdateTemplate(ctx, &req)
+
+		return err //nolint:wrapcheck
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("updating template: %w", err))
 	}
 
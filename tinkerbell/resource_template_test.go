@@ -1,11 +1,18 @@
 package tinkerbell
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/template"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func testAccTemplate(name, content string) string {
@@ -67,6 +74,17 @@ func TestAccTemplate_create(t *testing.T) {
 			{
 				Config: testAccTemplate(name, testAccTemplateContent(1)),
 			},
+			{
+				ResourceName:      "tinkerbell_template.a" + name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "tinkerbell_template.a" + name,
+				ImportState:       true,
+				ImportStateId:     name,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -131,3 +149,160 @@ func TestAccTemplate_validateData(t *testing.T) {
 		},
 	})
 }
+
+func testTemplateClient(entries ...*template.WorkflowTemplate) *template.TemplateServiceClientMock {
+	return &template.TemplateServiceClientMock{
+		ListTemplatesFunc: func(
+			ctx context.Context,
+			in *template.ListRequest,
+			opts ...grpc.CallOption,
+		) (template.TemplateService_ListTemplatesClient, error) {
+			entries := entries
+
+			return &template.TemplateService_ListTemplatesClientMock{
+				RecvFunc: func() (*template.WorkflowTemplate, error) {
+					if len(entries) == 0 {
+						return nil, io.EOF
+					}
+
+					t := entries[0]
+					entries = entries[1:]
+
+					return t, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestResourceTemplateImport(t *testing.T) {
+	t.Parallel()
+
+	client := testTemplateClient(
+		&template.WorkflowTemplate{Id: "foo-id", Name: "foo"},
+		&template.WorkflowTemplate{Id: "bar-id", Name: "bar"},
+		&template.WorkflowTemplate{Id: "baz-id-1", Name: "baz"},
+		&template.WorkflowTemplate{Id: "baz-id-2", Name: "baz"},
+	)
+
+	cases := map[string]struct {
+		id       string
+		expectID string
+		fail     bool
+	}{
+		"id": {
+			id:       "foo-id",
+			expectID: "foo-id",
+		},
+		"name": {
+			id:       "bar",
+			expectID: "bar-id",
+		},
+		"not_found": {
+			id:   "qux",
+			fail: true,
+		},
+		"ambiguous_name": {
+			id:   "baz",
+			fail: true,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := schema.TestResourceDataRaw(t, resourceTemplate().Schema, map[string]interface{}{})
+			d.SetId(c.id)
+
+			r, err := resourceTemplateImport(context.Background(), d, testTinkClientConfig(&tinkClient{templateClient: client}))
+			if c.fail {
+				if err == nil {
+					t.Fatalf("Expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Importing template: %v", err)
+			}
+
+			if got := r[0].Id(); got != c.expectID {
+				t.Fatalf("Expected template ID %q, got %q", c.expectID, got)
+			}
+		})
+	}
+}
+
+// testFailingCreateTemplateClient returns template client, which fails first create
+// request with given error and counts all create requests.
+func testFailingCreateTemplateClient(err error, calls *int) template.TemplateServiceClient {
+	return &template.TemplateServiceClientMock{
+		CreateTemplateFunc: func(
+			ctx context.Context,
+			in *template.WorkflowTemplate,
+			opts ...grpc.CallOption,
+		) (*template.CreateResponse, error) {
+			*calls++
+
+			if *calls == 1 {
+				return nil, err
+			}
+
+			return &template.CreateResponse{Id: "foo-id"}, nil
+		},
+	}
+}
+
+func TestResourceTemplateCreate_retries(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err           error
+		expectedCalls int
+		fail          bool
+	}{
+		"deadline_exceeded": {
+			err:           &timeoutError{err: status.Error(codes.DeadlineExceeded, "foo")},
+			expectedCalls: 1,
+			fail:          true,
+		},
+		"unavailable": {
+			err:           status.Error(codes.Unavailable, "foo"),
+			expectedCalls: 1,
+			fail:          true,
+		},
+		"not_sent": {
+			err:           &requestNotSentError{err: status.Error(codes.Unavailable, "foo")},
+			expectedCalls: 2,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+			client := testFailingCreateTemplateClient(c.err, &calls)
+
+			d := schema.TestResourceDataRaw(t, resourceTemplate().Schema, map[string]interface{}{
+				"name":    "foo",
+				"content": "foo",
+			})
+
+			diags := resourceTemplateCreate(context.Background(), d, testTinkClientConfig(&tinkClient{templateClient: client}))
+			if diags.HasError() != c.fail {
+				t.Fatalf("Expected failure to be %v, got: %v", c.fail, diags)
+			}
+
+			if calls != c.expectedCalls {
+				t.Fatalf("Expected %d calls, got %d", c.expectedCalls, calls)
+			}
+		})
+	}
+}
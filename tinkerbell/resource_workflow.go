@@ -2,13 +2,19 @@ package tinkerbell
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-
+	"github.com/tinkerbell/tink/protos/hardware"
+	"github.com/tinkerbell/tink/protos/template"
 	"github.com/tinkerbell/tink/protos/workflow"
 )
 
@@ -16,20 +22,208 @@ func resourceWorkflow() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceWorkflowCreate,
 		ReadContext:   resourceWorkflowRead,
+		UpdateContext: resourceWorkflowUpdate,
 		DeleteContext: resourceWorkflowDelete,
-		Schema: map[string]*schema.Schema{
-			"hardwares": {
-				Type:             schema.TypeString,
-				Required:         true,
-				ForceNew:         true,
-				ValidateDiagFunc: validateNotEmpty,
-				DiffSuppressFunc: suppressEquivalentJSONDiffs,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWorkflowImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Read:   schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
+		CustomizeDiff: customdiff.All(
+			resourceWorkflowDiffHardwareIDs,
+			resourceWorkflowDiffDevices,
+			resourceWorkflowDiffTemplateHash,
+			resourceWorkflowDiffDataVersion,
+		),
+		Schema: mergeSchemas(
+			workflowDefinitionSchema(),
+			workflowDataSchema(),
+			workflowStatusSchema(),
+			workflowEventsSchema(),
+		),
+	}
+}
+
+// workflowDefinitionSchema returns attributes defining the workflow and when it is replaced.
+func workflowDefinitionSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		hardwaresAttribute: {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ForceNew:         true,
+			ExactlyOneOf:     []string{hardwaresAttribute, hardwareMapAttribute},
+			ValidateDiagFunc: validateNotEmpty,
+			DiffSuppressFunc: suppressEquivalentJSONDiffs,
+			Description:      "JSON formatted map of device names to MAC or IP addresses of the hardware.",
+		},
+		hardwareMapAttribute: {
+			Type:             schema.TypeMap,
+			Optional:         true,
+			Computed:         true,
+			ForceNew:         true,
+			ExactlyOneOf:     []string{hardwaresAttribute, hardwareMapAttribute},
+			ValidateDiagFunc: validateHardwareMap,
+			Description:      "Map of device names to MAC or IP addresses or IDs of the hardware.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
 			},
-			"template": {
-				Type:             schema.TypeString,
-				Required:         true,
-				ForceNew:         true,
-				ValidateDiagFunc: validateNotEmpty,
+		},
+		"template": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ForceNew:         true,
+			ValidateDiagFunc: validateNotEmpty,
+		},
+		"triggers": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "Arbitrary values, which replace the workflow when changed.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"recreate_on_template_change": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Replace the workflow when content of the template changes.",
+		},
+		"template_hash": {
+			Type:     schema.TypeString,
+			Computed: true,
+			// Changed hash is only planned when the recorded hash differs from the current one.
+			ForceNew:    true,
+			Description: "SHA256 hash of the template content used to create the workflow.",
+		},
+		"data": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Workflow definition rendered from the template and the hardwares.",
+		},
+	}
+}
+
+// workflowDataSchema returns attributes managing the workflow ephemeral data and waiting for the workflow.
+func workflowDataSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"wait_for_state": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: validateWorkflowWaitState,
+			Description:      "Workflow state to wait for after creating the workflow. Only SUCCESS is supported.",
+		},
+		"poll_interval": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          defaultWorkflowPollInterval,
+			ValidateDiagFunc: validatePositiveDuration,
+			Description:      "How often to check the workflow state while waiting for it.",
+		},
+		workflowDataAttribute: {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: validateJSON,
+			DiffSuppressFunc: suppressEquivalentJSONDiffs,
+			Description:      "JSON formatted ephemeral data to store for the workflow actions.",
+		},
+		"workflow_data_version": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Latest version of the workflow ephemeral data.",
+		},
+	}
+}
+
+// workflowStatusSchema returns computed attributes describing the workflow progress.
+func workflowStatusSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Workflow state, one of PENDING, RUNNING, FAILED, TIMEOUT or SUCCESS.",
+		},
+		"current_task": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"current_action": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"current_worker": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"current_action_index": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"total_actions": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"created_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Workflow creation time in RFC3339 format.",
+		},
+		"updated_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Workflow last update time in RFC3339 format.",
+		},
+	}
+}
+
+// workflowEventsSchema returns attributes exposing events reported by the workflow.
+func workflowEventsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"events_limit": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ValidateDiagFunc: validateNotNegative,
+			Description:      "Maximum number of most recent events to keep in 'events' attribute. 0 means no limit.",
+		},
+		"events": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Events reported by the workflow, oldest first.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"task_name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"action_name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"status": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"seconds": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"message": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"worker_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"created_at": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
 			},
 		},
 	}
@@ -43,27 +237,389 @@ func resourceWorkflowCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 	c := tc.workflowClient
 
+	hardwares, err := workflowHardwares(ctx, tc, d)
+	if err != nil {
+		return diagsFromErr(err)
+	}
+
+	if err := recordTemplateHash(ctx, tc, d); err != nil {
+		return diagsFromErr(err)
+	}
+
 	req := workflow.CreateRequest{
 		Template: d.Get("template").(string),
-		Hardware: d.Get("hardwares").(string),
+		Hardware: hardwares,
 	}
 
-	res, err := c.CreateWorkflow(ctx, &req)
-	if err != nil {
+	var res *workflow.CreateResponse
+
+	if err := tc.write(ctx, func() error {
+		res, err = c.CreateWorkflow(ctx, &req)
+
+		return err //nolint:wrapcheck
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("creating workflow: %w", err))
 	}
 
 	d.SetId(res.Id)
 
+	if data := d.Get(workflowDataAttribute).(string); data != "" {
+		if err := updateWorkflowData(ctx, tc, res.Id, data); err != nil {
+			return diagsFromErr(err)
+		}
+	}
+
+	if d.Get("wait_for_state").(string) != "" {
+		// Validate function should already validate it.
+		interval, _ := time.ParseDuration(d.Get("poll_interval").(string))
+
+		if err := waitForWorkflow(ctx, tc, res.Id, interval); err != nil {
+			return diagsFromErr(fmt.Errorf("waiting for workflow: %w", err))
+		}
+	}
+
+	return resourceWorkflowRead(ctx, d, m)
+}
+
+// workflowHardwares returns JSON formatted hardwares to create the workflow with,
+// resolving 'hardware_map' attribute if it's set.
+func workflowHardwares(ctx context.Context, tc *tinkClient, d *schema.ResourceData) (string, error) {
+	devices := d.Get(hardwareMapAttribute).(map[string]interface{})
+	if len(devices) == 0 {
+		return d.Get(hardwaresAttribute).(string), nil
+	}
+
+	// Template may not be known during planning, so check it again.
+	if err := checkDeviceReferences(ctx, tc, d.Get("template").(string), devices); err != nil {
+		return "", err
+	}
+
+	resolved, err := resolveHardwareMap(ctx, tc, devices)
+	if err != nil {
+		return "", err
+	}
+
+	return expandHardwareMap(resolved)
+}
+
+// resourceWorkflowDiffDevices checks if all devices referenced in the template are
+// defined in the hardware map, if both are known.
+func resourceWorkflowDiffDevices(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange(hardwareMapAttribute) && !d.HasChange("template") {
+		return nil
+	}
+
+	if !d.NewValueKnown(hardwareMapAttribute) || !d.NewValueKnown("template") {
+		return nil
+	}
+
+	devices := d.Get(hardwareMapAttribute).(map[string]interface{})
+	if len(devices) == 0 {
+		return nil
+	}
+
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return fmt.Errorf("creating Tink client: %w", err)
+	}
+
+	return checkDeviceReferences(ctx, tc, d.Get("template").(string), devices)
+}
+
+// resourceWorkflowDiffHardwareIDs clears planned 'hardware_map' changes if all configured
+// hardware IDs resolve to the addresses stored for the workflow, e.g. after the workflow
+// is imported, so the workflow is not replaced.
+func resourceWorkflowDiffHardwareIDs(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange(hardwareMapAttribute) || !d.NewValueKnown(hardwareMapAttribute) {
+		return nil
+	}
+
+	o, n := d.GetChange(hardwareMapAttribute)
+	stored, _ := o.(map[string]interface{})
+	devices, _ := n.(map[string]interface{})
+
+	if len(stored) != len(devices) {
+		return nil
+	}
+
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return fmt.Errorf("creating Tink client: %w", err)
+	}
+
+	for device, v := range devices {
+		id, _ := v.(string)
+
+		addr, ok := stored[device].(string)
+		if !ok || (id != addr && !isHardwareID(id)) {
+			return nil
+		}
+
+		if id == addr {
+			continue
+		}
+
+		mac, err := hardwareMAC(ctx, tc, id)
+		if err != nil {
+			return fmt.Errorf("resolving device %q: %w", device, err)
+		}
+
+		if !strings.EqualFold(mac, addr) {
+			return nil
+		}
+	}
+
+	if err := d.Clear(hardwareMapAttribute); err != nil {
+		return fmt.Errorf("clearing %q changes: %w", hardwareMapAttribute, err)
+	}
+
+	return nil
+}
+
+// resourceWorkflowDiffTemplateHash forces replacement of the workflow if content of
+// the template changed since the workflow was created. Content is read from the Tink
+// server, so changes planned for the template are only detected once they are applied.
+func resourceWorkflowDiffTemplateHash(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.Get("recreate_on_template_change").(bool) {
+		return nil
+	}
+
+	// Changed template replaces the workflow anyway.
+	if d.HasChange("template") || !d.NewValueKnown("template") {
+		return nil
+	}
+
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return fmt.Errorf("creating Tink client: %w", err)
+	}
+
+	t, err := getTemplateByID(ctx, tc, d.Get("template").(string))
+	if err != nil {
+		return err
+	}
+
+	// Removed template is reported when creating a new workflow.
+	if t == nil {
+		return nil
+	}
+
+	// Hash is not known if the option has just been enabled, so it's recorded
+	// on update without replacing the workflow.
+	old := d.Get("template_hash").(string)
+	if hash := templateHash(t.GetData()); old != "" && old != hash {
+		// Attribute schema forces replacement of the workflow.
+		if err := d.SetNew("template_hash", hash); err != nil {
+			return fmt.Errorf("setting %q: %w", "template_hash", err)
+		}
+	}
+
+	return nil
+}
+
+// recordTemplateHash sets 'template_hash' attribute to the hash of the current template
+// content, if 'recreate_on_template_change' is enabled.
+func recordTemplateHash(ctx context.Context, tc *tinkClient, d *schema.ResourceData) error {
+	if !d.Get("recreate_on_template_change").(bool) {
+		return nil
+	}
+
+	t, err := getTemplateByID(ctx, tc, d.Get("template").(string))
+	if err != nil {
+		return err
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	if err := d.Set("template_hash", templateHash(t.GetData())); err != nil {
+		return fmt.Errorf("setting %q field: %w", "template_hash", err)
+	}
+
 	return nil
 }
 
-func getWorkflow(ctx context.Context, c workflow.WorkflowServiceClient, uuid string) (*workflow.Workflow, error) {
+// resourceWorkflowDiffDataVersion marks data version as unknown if workflow data
+// is going to be updated.
+func resourceWorkflowDiffDataVersion(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange(workflowDataAttribute) || d.Get(workflowDataAttribute).(string) == "" {
+		return nil
+	}
+
+	if err := d.SetNewComputed("workflow_data_version"); err != nil {
+		return fmt.Errorf("marking %q as computed: %w", "workflow_data_version", err)
+	}
+
+	return nil
+}
+
+// templateHash returns hex encoded SHA256 hash of given template content.
+func templateHash(content string) string {
+	h := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(h[:])
+}
+
+// getTemplateByID returns template with given ID or nil if it does not exist.
+func getTemplateByID(ctx context.Context, tc *tinkClient, id string) (*template.WorkflowTemplate, error) {
+	var t *template.WorkflowTemplate
+
+	err := tc.retry(ctx, func() error {
+		var err error
+
+		t, err = tc.templateClient.GetTemplate(ctx, &template.GetRequest{
+			GetBy: &template.GetRequest_Id{
+				Id: id,
+			},
+		})
+
+		return err //nolint:wrapcheck
+	})
+
+	switch {
+	case err == nil:
+		return t, nil
+	case isNotFound(err):
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("getting template %q: %w", id, err)
+	}
+}
+
+// checkDeviceReferences checks if all devices referenced in the template with given ID
+// are defined in given devices map.
+func checkDeviceReferences(
+	ctx context.Context,
+	tc *tinkClient,
+	templateID string,
+	devices map[string]interface{},
+) error {
+	t, err := getTemplateByID(ctx, tc, templateID)
+	if err != nil {
+		return err
+	}
+
+	if t == nil {
+		return fmt.Errorf("template %q does not exist", templateID)
+	}
+
+	if err := validateDeviceReferences(t.GetData(), devices); err != nil {
+		return fmt.Errorf("checking %q against template %q: %w", hardwareMapAttribute, templateID, err)
+	}
+
+	return nil
+}
+
+// resolveHardwareMap returns copy of given devices map with hardware IDs replaced by
+// MAC address of the first interface of the hardware.
+func resolveHardwareMap(
+	ctx context.Context,
+	tc *tinkClient,
+	devices map[string]interface{},
+) (map[string]interface{}, error) {
+	resolved := map[string]interface{}{}
+
+	for device, v := range devices {
+		addr, _ := v.(string)
+
+		if !isHardwareID(addr) {
+			resolved[device] = addr
+
+			continue
+		}
+
+		mac, err := hardwareMAC(ctx, tc, addr)
+		if err != nil {
+			return nil, fmt.Errorf("resolving device %q: %w", device, err)
+		}
+
+		if mac == "" {
+			return nil, fmt.Errorf("resolving device %q: hardware %q does not exist or has no interfaces with MAC address",
+				device, addr)
+		}
+
+		resolved[device] = mac
+	}
+
+	return resolved, nil
+}
+
+// hardwareMAC returns MAC address of the first interface of the hardware with given ID
+// or empty string if hardware does not exist.
+func hardwareMAC(ctx context.Context, tc *tinkClient, id string) (string, error) {
+	var hw *hardware.Hardware
+
+	if err := tc.retry(ctx, func() error {
+		var err error
+
+		hw, err = getHardware(ctx, tc.hardwareClient, tc.inventory, id)
+
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("getting hardware %q: %w", id, err)
+	}
+
+	for _, i := range hw.GetNetwork().GetInterfaces() {
+		if mac := i.GetDhcp().GetMac(); mac != "" {
+			return mac, nil
+		}
+	}
+
+	return "", nil
+}
+
+// resourceWorkflowUpdate stores new version of the workflow data if it changed. Other
+// updatable attributes only affect the provider behavior. Removing the workflow data
+// from the configuration keeps the stored data intact.
+func resourceWorkflowUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("creating Tink client: %w", err))
+	}
+
+	if d.Get("template_hash").(string) == "" {
+		if err := recordTemplateHash(ctx, tc, d); err != nil {
+			return diagsFromErr(err)
+		}
+	}
+
+	if data := d.Get(workflowDataAttribute).(string); d.HasChange(workflowDataAttribute) && data != "" {
+		if err := updateWorkflowData(ctx, tc, d.Id(), data); err != nil {
+			return diagsFromErr(err)
+		}
+	}
+
+	return resourceWorkflowRead(ctx, d, m)
+}
+
+// getWorkflow returns workflow with given ID or nil if it does not exist.
+func getWorkflow(
+	ctx context.Context,
+	c workflow.WorkflowServiceClient,
+	inv *inventory,
+	uuid string,
+) (*workflow.Workflow, error) {
+	entries, err := inv.get(ctx, workflowInventory, func(ctx context.Context) (map[string]interface{}, error) {
+		return listWorkflows(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wf, _ := entries[uuid].(*workflow.Workflow)
+
+	return wf, nil
+}
+
+func listWorkflows(ctx context.Context, c workflow.WorkflowServiceClient) (map[string]interface{}, error) {
 	list, err := c.ListWorkflows(ctx, &workflow.Empty{})
 	if err != nil {
 		return nil, fmt.Errorf("getting all workflow entries: %w", err)
 	}
 
+	entries := map[string]interface{}{}
+
 	for {
 		wf, err := list.Recv()
 		if err != nil {
@@ -78,12 +634,10 @@ func getWorkflow(ctx context.Context, c workflow.WorkflowServiceClient, uuid str
 			return nil, fmt.Errorf("received empty workflow entry: %w", err)
 		}
 
-		if wf.GetId() == uuid {
-			return wf, nil
-		}
+		entries[wf.GetId()] = wf
 	}
 
-	return nil, nil
+	return entries, nil
 }
 
 func resourceWorkflowRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -94,8 +648,13 @@ func resourceWorkflowRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	c := tc.workflowClient
 
-	wf, err := getWorkflow(ctx, c, d.Id())
-	if err != nil {
+	var wf *workflow.Workflow
+
+	if err := tc.retry(ctx, func() error {
+		wf, err = getWorkflow(ctx, c, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("getting workflow %q: %w", d.Id(), err))
 	}
 
@@ -105,9 +664,169 @@ func resourceWorkflowRead(ctx context.Context, d *schema.ResourceData, m interfa
 		return nil
 	}
 
+	if err := tc.retry(ctx, func() error {
+		wf, err = c.GetWorkflow(ctx, &workflow.GetRequest{Id: d.Id()})
+
+		return err //nolint:wrapcheck
+	}); err != nil {
+		return diagsFromErr(fmt.Errorf("getting workflow %q: %w", d.Id(), err))
+	}
+
+	attrs, err := workflowStatusAttributes(ctx, tc, d)
+	if err != nil {
+		return diagsFromErr(err)
+	}
+
+	devices, err := readHardwareMap(ctx, tc, wf.GetHardware(), d.Get(hardwareMapAttribute).(map[string]interface{}))
+	if err != nil {
+		return diagsFromErr(fmt.Errorf("reading workflow %q hardwares: %w", d.Id(), err))
+	}
+
+	attrs[hardwareMapAttribute] = devices
+	attrs["template"] = wf.GetTemplate()
+	attrs[hardwaresAttribute] = wf.GetHardware()
+	attrs["data"] = wf.GetData()
+	attrs["created_at"] = formatTimestamp(wf.GetCreatedAt())
+	attrs["updated_at"] = formatTimestamp(wf.GetUpdatedAt())
+
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			return diagsFromErr(fmt.Errorf("setting %q field: %w", k, err))
+		}
+	}
+
 	return nil
 }
 
+// workflowStatusAttributes returns values of attributes describing the workflow
+// progress, its events and the workflow data version.
+func workflowStatusAttributes(
+	ctx context.Context,
+	tc *tinkClient,
+	d *schema.ResourceData,
+) (map[string]interface{}, error) {
+	var wc *workflow.WorkflowContext
+
+	if err := tc.retry(ctx, func() error {
+		var err error
+
+		wc, err = tc.workflowClient.GetWorkflowContext(ctx, &workflow.GetRequest{Id: d.Id()})
+
+		return err //nolint:wrapcheck
+	}); err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("getting workflow %q context: %w", d.Id(), err)
+	}
+
+	var events []*workflow.WorkflowActionStatus
+
+	if err := tc.retry(ctx, func() error {
+		var err error
+
+		events, err = getWorkflowEvents(ctx, tc.workflowClient, d.Id())
+
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("getting workflow %q events: %w", d.Id(), err)
+	}
+
+	// Workflow data is not refreshed, as it's modified by the workflow actions.
+	dataVersion, err := getWorkflowDataVersion(ctx, tc, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"events":                flattenWorkflowEvents(events, d.Get("events_limit").(int)),
+		"workflow_data_version": dataVersion,
+		"state":                 workflowStateName(workflowState(wc)),
+		"current_task":          wc.GetCurrentTask(),
+		"current_action":        wc.GetCurrentAction(),
+		"current_worker":        wc.GetCurrentWorker(),
+		"current_action_index":  int(wc.GetCurrentActionIndex()),
+		"total_actions":         int(wc.GetTotalNumberOfActions()),
+	}, nil
+}
+
+// readHardwareMap converts JSON formatted hardwares of the workflow into 'hardware_map'
+// attribute value. Hardware IDs from the configured value are kept as long as they
+// still resolve to the addresses used by the workflow, so changed MAC addresses are
+// detected.
+func readHardwareMap(
+	ctx context.Context,
+	tc *tinkClient,
+	hardwares string,
+	configured map[string]interface{},
+) (map[string]interface{}, error) {
+	devices, err := flattenHardwareMap(hardwares)
+	if err != nil {
+		return nil, err
+	}
+
+	for device, v := range configured {
+		id, _ := v.(string)
+
+		addr, ok := devices[device].(string)
+		if !ok || !isHardwareID(id) {
+			continue
+		}
+
+		mac, err := hardwareMAC(ctx, tc, id)
+		if err != nil {
+			return nil, fmt.Errorf("resolving device %q: %w", device, err)
+		}
+
+		if strings.EqualFold(mac, addr) {
+			devices[device] = id
+		}
+	}
+
+	return devices, nil
+}
+
+// resourceWorkflowImport imports workflow by ID. Latest workflow data is imported as
+// well if there is any, so configuration matching it does not store a new version.
+func resourceWorkflowImport(
+	ctx context.Context,
+	d *schema.ResourceData,
+	m interface{},
+) ([]*schema.ResourceData, error) {
+	tc, err := m.(*tinkClientConfig).New()
+	if err != nil {
+		return nil, fmt.Errorf("creating Tink client: %w", err)
+	}
+
+	var res *workflow.GetWorkflowDataResponse
+
+	if err := tc.retry(ctx, func() error {
+		res, err = tc.workflowClient.GetWorkflowData(ctx, &workflow.GetWorkflowDataRequest{
+			WorkflowId: d.Id(),
+		})
+
+		return err //nolint:wrapcheck
+	}); err != nil {
+		return nil, fmt.Errorf("getting workflow %q data: %w", d.Id(), err)
+	}
+
+	// Defaults are not applied when importing.
+	attrs := map[string]interface{}{
+		"poll_interval": defaultWorkflowPollInterval,
+	}
+
+	// Workflow data is not refreshed, so it's only set when there is some to keep
+	// the configuration without it in sync.
+	if data := string(res.GetData()); data != "" {
+		attrs[workflowDataAttribute] = data
+	}
+
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			return nil, fmt.Errorf("setting %q field: %w", k, err)
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceWorkflowDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	tc, err := m.(*tinkClientConfig).New()
 	if err != nil {
@@ -116,8 +835,13 @@ func resourceWorkflowDelete(ctx context.Context, d *schema.ResourceData, m inter
 
 	c := tc.workflowClient
 
-	wf, err := getWorkflow(ctx, c, d.Id())
-	if err != nil {
+	var wf *workflow.Workflow
+
+	if err := tc.retry(ctx, func() error {
+		wf, err = getWorkflow(ctx, c, tc.inventory, d.Id())
+
+		return err
+	}); err != nil {
 		return diagsFromErr(fmt.Errorf("getting workflow %q: %w", d.Id(), err))
 	}
 
@@ -131,7 +855,7 @@ func resourceWorkflowDelete(ctx context.Context, d *schema.ResourceData, m inter
 		Id: d.Id(),
 	}
 
-	if err := retryOnTransientError(func() error {
+	if err := tc.write(ctx, func() error {
 		_, err := c.DeleteWorkflow(ctx, &req)
 
 		return err //nolint:wrapcheck
@@ -1,10 +1,21 @@
 package tinkerbell
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/tinkerbell/tink/protos/hardware"
+	"github.com/tinkerbell/tink/protos/template"
+	"github.com/tinkerbell/tink/protos/workflow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func testAccWorkflow(t *testing.T, id int) string {
@@ -46,6 +57,84 @@ func TestAccWorkflow_basic(t *testing.T) {
 		Steps: []resource.TestStep{
 			{
 				Config: testAccWorkflow(t, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("tinkerbell_workflow.foo0", "data"),
+					resource.TestCheckResourceAttr("tinkerbell_workflow.foo0", "state", "PENDING"),
+					resource.TestCheckResourceAttrSet("tinkerbell_workflow.foo0", "created_at"),
+				),
+			},
+			{
+				ResourceName:      "tinkerbell_workflow.foo0",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccWorkflowHardwareMap returns configuration of workflow referencing hardware
+// by MAC address or by ID, if byID is true.
+func testAccWorkflowHardwareMap(t *testing.T, byID bool) string {
+	name := newUUID(t)
+	rMAC := newMAC(t)
+
+	device := fmt.Sprintf("%q", rMAC)
+	if byID {
+		device = "tinkerbell_hardware.foo.id"
+	}
+
+	return fmt.Sprintf(`
+%s
+
+%s
+
+resource "tinkerbell_workflow" "foo" {
+	template = tinkerbell_template.a%s.id
+
+	hardware_map = {
+		device_1 = %s
+	}
+
+	depends_on = [
+		tinkerbell_hardware.foo,
+	]
+}
+`,
+		testAccHardware(testAccHardwareConfig(name, rMAC), "foo"),
+		testAccTemplate(name, testAccTemplateContent(1)),
+		name,
+		device,
+	)
+}
+
+func TestAccWorkflow_hardwareMap(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkflowHardwareMap(t, false),
+				Check:  resource.TestCheckResourceAttrSet("tinkerbell_workflow.foo", "hardwares"),
+			},
+		},
+	})
+}
+
+func TestAccWorkflow_hardwareID(t *testing.T) {
+	t.Parallel()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkflowHardwareMap(t, true),
+				Check: resource.TestCheckResourceAttrPair(
+					"tinkerbell_workflow.foo", "hardware_map.device_1",
+					"tinkerbell_hardware.foo", "id",
+				),
 			},
 		},
 	})
@@ -69,3 +158,558 @@ func TestAccWorkflow_parallel(t *testing.T) {
 		},
 	})
 }
+
+func testListWorkflowsClient(entries ...*workflow.Workflow) *workflow.WorkflowService_ListWorkflowsClientMock {
+	return &workflow.WorkflowService_ListWorkflowsClientMock{
+		RecvFunc: func() (*workflow.Workflow, error) {
+			if len(entries) == 0 {
+				return nil, io.EOF
+			}
+
+			wf := entries[0]
+			entries = entries[1:]
+
+			return wf, nil
+		},
+	}
+}
+
+// testReadWorkflowClient returns workflow client mock returning given workflow,
+// its context, events and workflow data version.
+func testReadWorkflowClient(
+	wf *workflow.Workflow,
+	wc *workflow.WorkflowContext,
+	events []*workflow.WorkflowActionStatus,
+	version int32,
+) *workflow.WorkflowServiceClientMock {
+	return &workflow.WorkflowServiceClientMock{
+		ListWorkflowsFunc: func(
+			ctx context.Context,
+			in *workflow.Empty,
+			opts ...grpc.CallOption,
+		) (workflow.WorkflowService_ListWorkflowsClient, error) {
+			return testListWorkflowsClient(&workflow.Workflow{Id: wf.Id}), nil
+		},
+		GetWorkflowFunc: func(
+			ctx context.Context,
+			in *workflow.GetRequest,
+			opts ...grpc.CallOption,
+		) (*workflow.Workflow, error) {
+			return wf, nil
+		},
+		GetWorkflowContextFunc: func(
+			ctx context.Context,
+			in *workflow.GetRequest,
+			opts ...grpc.CallOption,
+		) (*workflow.WorkflowContext, error) {
+			return wc, nil
+		},
+		ShowWorkflowEventsFunc: func(
+			ctx context.Context,
+			in *workflow.GetRequest,
+			opts ...grpc.CallOption,
+		) (workflow.WorkflowService_ShowWorkflowEventsClient, error) {
+			return &testShowWorkflowEventsClient{events: events}, nil
+		},
+		GetWorkflowDataVersionFunc: func(
+			ctx context.Context,
+			in *workflow.GetWorkflowDataRequest,
+			opts ...grpc.CallOption,
+		) (*workflow.GetWorkflowDataResponse, error) {
+			return &workflow.GetWorkflowDataResponse{Version: version}, nil
+		},
+	}
+}
+
+func TestResourceWorkflowRead_detectsDrift(t *testing.T) {
+	t.Parallel()
+
+	wf := &workflow.Workflow{
+		Id:       "foo",
+		Template: "new-template",
+		Hardware: `{"device_1":"ff:ff:ff:ff:ff:ff"}`,
+		Data:     "version: 0.1",
+	}
+
+	wc := &workflow.WorkflowContext{
+		WorkflowId:           "foo",
+		CurrentTask:          "os-installation",
+		CurrentActionState:   workflow.State_STATE_SUCCESS,
+		CurrentActionIndex:   1,
+		TotalNumberOfActions: 2,
+	}
+
+	events := []*workflow.WorkflowActionStatus{
+		{ActionName: "disk-wipe", ActionStatus: workflow.State_STATE_SUCCESS},
+	}
+
+	client := testReadWorkflowClient(wf, wc, events, 3)
+
+	d := schema.TestResourceDataRaw(t, resourceWorkflow().Schema, map[string]interface{}{
+		"template":  "old-template",
+		"hardwares": `{"device_1":"00:00:00:00:00:00"}`,
+	})
+	d.SetId("foo")
+
+	tc := testTinkClientConfig(&tinkClient{workflowClient: client})
+
+	if diags := resourceWorkflowRead(context.Background(), d, tc); diags.HasError() {
+		t.Fatalf("Reading workflow: %v", diags)
+	}
+
+	expected := map[string]interface{}{
+		"template":              "new-template",
+		"hardwares":             `{"device_1":"ff:ff:ff:ff:ff:ff"}`,
+		"data":                  "version: 0.1",
+		"state":                 workflowStateSuccess,
+		"hardware_map.device_1": "ff:ff:ff:ff:ff:ff",
+		"total_actions":         2,
+		"events.0.action_name":  "disk-wipe",
+		"workflow_data_version": 3,
+	}
+
+	for k, v := range expected {
+		if got := d.Get(k); got != v {
+			t.Errorf("Expected %q to be %v, got %v", k, v, got)
+		}
+	}
+}
+
+func TestResourceWorkflowRead_removed(t *testing.T) {
+	t.Parallel()
+
+	client := &workflow.WorkflowServiceClientMock{
+		ListWorkflowsFunc: func(
+			ctx context.Context,
+			in *workflow.Empty,
+			opts ...grpc.CallOption,
+		) (workflow.WorkflowService_ListWorkflowsClient, error) {
+			return testListWorkflowsClient(), nil
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceWorkflow().Schema, map[string]interface{}{})
+	d.SetId("foo")
+
+	tc := testTinkClientConfig(&tinkClient{workflowClient: client})
+
+	if diags := resourceWorkflowRead(context.Background(), d, tc); diags.HasError() {
+		t.Fatalf("Reading workflow: %v", diags)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("Expected removed workflow to be removed from state")
+	}
+}
+
+func testHardwareClientWithMAC(id, mac string) *hardware.HardwareServiceClientMock {
+	return &hardware.HardwareServiceClientMock{
+		ByIDFunc: func(ctx context.Context, in *hardware.GetRequest, opts ...grpc.CallOption) (*hardware.Hardware, error) {
+			if in.Id != id {
+				return nil, status.Error(codes.NotFound, "not found")
+			}
+
+			return &hardware.Hardware{
+				Id: id,
+				Network: &hardware.Hardware_Network{
+					Interfaces: []*hardware.Hardware_Network_Interface{
+						{Dhcp: &hardware.Hardware_DHCP{Mac: mac}},
+					},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestResolveHardwareMap(t *testing.T) {
+	t.Parallel()
+
+	id := "2bd4b2b3-3104-4f67-8b5c-3d208d9cd1cd"
+
+	tc := &tinkClient{
+		retryPolicy:    testRetryPolicy(),
+		hardwareClient: testHardwareClientWithMAC(id, "ff:ff:ff:ff:ff:ff"),
+	}
+
+	got, err := resolveHardwareMap(context.Background(), tc, map[string]interface{}{
+		"device_1": id,
+		"device_2": "192.168.1.5",
+	})
+	if err != nil {
+		t.Fatalf("Resolving hardware map: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"device_1": "ff:ff:ff:ff:ff:ff",
+		"device_2": "192.168.1.5",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+
+	if _, err := resolveHardwareMap(context.Background(), tc, map[string]interface{}{"device_1": newUUID(t)}); err == nil {
+		t.Fatalf("Resolving non-existing hardware ID should fail")
+	}
+}
+
+func TestResourceWorkflowRead_hardwareIDs(t *testing.T) {
+	t.Parallel()
+
+	id := "2bd4b2b3-3104-4f67-8b5c-3d208d9cd1cd"
+
+	cases := map[string]struct {
+		mac      string
+		expected string
+	}{
+		"same_mac": {
+			mac:      "FF:FF:FF:FF:FF:FF",
+			expected: id,
+		},
+		"changed_mac": {
+			mac:      "00:00:00:00:00:01",
+			expected: "ff:ff:ff:ff:ff:ff",
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			wf := &workflow.Workflow{Id: "foo", Hardware: `{"device_1":"ff:ff:ff:ff:ff:ff"}`}
+			client := testReadWorkflowClient(wf, &workflow.WorkflowContext{}, nil, 0)
+
+			d := schema.TestResourceDataRaw(t, resourceWorkflow().Schema, map[string]interface{}{
+				"template": "bar",
+				"hardware_map": map[string]interface{}{
+					"device_1": id,
+				},
+			})
+			d.SetId("foo")
+
+			tc := &tinkClient{
+				workflowClient: client,
+				hardwareClient: testHardwareClientWithMAC(id, c.mac),
+			}
+
+			if diags := resourceWorkflowRead(context.Background(), d, testTinkClientConfig(tc)); diags.HasError() {
+				t.Fatalf("Reading workflow: %v", diags)
+			}
+
+			if got := d.Get("hardware_map.device_1"); got != c.expected {
+				t.Fatalf("Expected device_1 to be %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+// testTemplateClientWithContent returns template client mock returning templates
+// with given content.
+func testTemplateClientWithContent(content string) *template.TemplateServiceClientMock {
+	return &template.TemplateServiceClientMock{
+		GetTemplateFunc: func(
+			ctx context.Context,
+			in *template.GetRequest,
+			opts ...grpc.CallOption,
+		) (*template.WorkflowTemplate, error) {
+			return &template.WorkflowTemplate{Id: in.GetId(), Data: content}, nil
+		},
+	}
+}
+
+// testWorkflowStateWithTemplateHash returns state of the workflow created with
+// 'recreate_on_template_change' enabled and given template hash recorded.
+func testWorkflowStateWithTemplateHash(hash string) *terraform.InstanceState {
+	return &terraform.InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"id":                          "foo",
+			"template":                    "bar",
+			"hardwares":                   `{"device_1":"ff:ff:ff:ff:ff:ff"}`,
+			"recreate_on_template_change": "true",
+			"poll_interval":               defaultWorkflowPollInterval,
+			"template_hash":               hash,
+			"hardware_map.%":              "1",
+			"hardware_map.device_1":       "ff:ff:ff:ff:ff:ff",
+			"events.#":                    "0",
+			"data":                        "",
+			"state":                       "",
+			"current_task":                "",
+			"current_action":              "",
+			"current_worker":              "",
+			"current_action_index":        "0",
+			"total_actions":               "0",
+			"workflow_data_version":       "0",
+			"created_at":                  "",
+			"updated_at":                  "",
+		},
+	}
+}
+
+func TestResourceWorkflowDiff_templateChanged(t *testing.T) {
+	t.Parallel()
+
+	content := testAccTemplateContent(1)
+
+	// Diffs are calculated in order using the same resource, so replacement forced
+	// for one workflow must not affect the others.
+	cases := []struct {
+		name        string
+		hash        string
+		requiresNew bool
+	}{
+		{
+			name:        "changed",
+			hash:        templateHash(testAccTemplateContent(2)),
+			requiresNew: true,
+		},
+		{
+			name: "unchanged",
+			hash: templateHash(content),
+		},
+		{
+			name: "not_recorded",
+		},
+		{
+			name:        "changed_again",
+			hash:        templateHash(testAccTemplateContent(3)),
+			requiresNew: true,
+		},
+	}
+
+	r := Provider().ResourcesMap["tinkerbell_workflow"]
+	tc := testTinkClientConfig(&tinkClient{templateClient: testTemplateClientWithContent(content)})
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"template":                    "bar",
+		"hardwares":                   `{"device_1":"ff:ff:ff:ff:ff:ff"}`,
+		"recreate_on_template_change": true,
+	})
+
+	for _, c := range cases {
+		diff, err := r.Diff(context.Background(), testWorkflowStateWithTemplateHash(c.hash), config, tc)
+		if err != nil {
+			t.Fatalf("%s: calculating diff: %v", c.name, err)
+		}
+
+		if got := diff.RequiresNew(); got != c.requiresNew {
+			t.Fatalf("%s: expected requires new to be %v, got %v, diff: %v", c.name, c.requiresNew, got, diff)
+		}
+
+		if c.hash == "" && diff != nil && diff.Attributes["template_hash"] != nil {
+			t.Fatalf("%s: expected template hash not to be planned, got diff: %v", c.name, diff)
+		}
+	}
+}
+
+func TestResourceWorkflowUpdate_recordsTemplateHash(t *testing.T) {
+	t.Parallel()
+
+	content := testAccTemplateContent(1)
+
+	wf := &workflow.Workflow{Id: "foo", Template: "bar", Hardware: `{"device_1":"ff:ff:ff:ff:ff:ff"}`}
+
+	tc := &tinkClient{
+		workflowClient: testReadWorkflowClient(wf, &workflow.WorkflowContext{}, nil, 0),
+		templateClient: testTemplateClientWithContent(content),
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceWorkflow().Schema, map[string]interface{}{
+		"template":                    "bar",
+		"hardwares":                   `{"device_1":"ff:ff:ff:ff:ff:ff"}`,
+		"recreate_on_template_change": true,
+	})
+	d.SetId("foo")
+
+	if diags := resourceWorkflowUpdate(context.Background(), d, testTinkClientConfig(tc)); diags.HasError() {
+		t.Fatalf("Updating workflow: %v", diags)
+	}
+
+	if got := d.Get("template_hash"); got != templateHash(content) {
+		t.Fatalf("Expected template hash %q to be recorded, got %q", templateHash(content), got)
+	}
+}
+
+func TestResourceWorkflowImport(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"with_data":    `{"disk":"/dev/sda"}`,
+		"without_data": "",
+	}
+
+	for name, data := range cases {
+		data := data
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &workflow.WorkflowServiceClientMock{
+				GetWorkflowDataFunc: func(
+					ctx context.Context,
+					in *workflow.GetWorkflowDataRequest,
+					opts ...grpc.CallOption,
+				) (*workflow.GetWorkflowDataResponse, error) {
+					return &workflow.GetWorkflowDataResponse{Data: []byte(data)}, nil
+				},
+			}
+
+			d := schema.TestResourceDataRaw(t, resourceWorkflow().Schema, map[string]interface{}{})
+			d.SetId("foo")
+
+			tc := testTinkClientConfig(&tinkClient{workflowClient: client})
+
+			r, err := resourceWorkflowImport(context.Background(), d, tc)
+			if err != nil {
+				t.Fatalf("Importing workflow: %v", err)
+			}
+
+			if got := r[0].Get("poll_interval"); got != defaultWorkflowPollInterval {
+				t.Errorf("Expected poll interval to be %q, got %q", defaultWorkflowPollInterval, got)
+			}
+
+			if got, ok := r[0].GetOk("workflow_data"); got != data || ok != (data != "") {
+				t.Errorf("Expected workflow data to be %q, got %q (set: %v)", data, got, ok)
+			}
+		})
+	}
+}
+
+func TestResourceWorkflowDiff_importedHardwareIDs(t *testing.T) {
+	t.Parallel()
+
+	id := "2bd4b2b3-3104-4f67-8b5c-3d208d9cd1cd"
+
+	cases := map[string]struct {
+		mac         string
+		requiresNew bool
+	}{
+		"same_mac": {
+			mac: "FF:FF:FF:FF:FF:FF",
+		},
+		"changed_mac": {
+			mac:         "00:00:00:00:00:01",
+			requiresNew: true,
+		},
+	}
+
+	r := Provider().ResourcesMap["tinkerbell_workflow"]
+
+	// Imported state holds addresses stored in the workflow.
+	state := testWorkflowStateWithTemplateHash("")
+	state.Attributes["recreate_on_template_change"] = "false"
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"template": "bar",
+		"hardware_map": map[string]interface{}{
+			"device_1": id,
+		},
+	})
+
+	for name, c := range cases {
+		tc := testTinkClientConfig(&tinkClient{
+			hardwareClient: testHardwareClientWithMAC(id, c.mac),
+			templateClient: testTemplateClientWithContent(testAccTemplateContent(1)),
+		})
+
+		diff, err := r.Diff(context.Background(), state, config, tc)
+		if err != nil {
+			t.Fatalf("%s: calculating diff: %v", name, err)
+		}
+
+		if got := diff.RequiresNew(); got != c.requiresNew {
+			t.Fatalf("%s: expected requires new to be %v, got %v, diff: %v", name, c.requiresNew, got, diff)
+		}
+
+		if !c.requiresNew && !diff.Empty() {
+			t.Fatalf("%s: expected empty diff, got: %v", name, diff)
+		}
+	}
+}
+
+// testWorkflowWithTemplateTrigger returns state of the workflow with given template hash
+// recorded and configuration of it. If triggers are enabled, the state tracks the recorded
+// hash and the configuration tracks given planned hash.
+func testWorkflowWithTemplateTrigger(
+	hash string,
+	planned string,
+	triggers bool,
+) (*terraform.InstanceState, *terraform.ResourceConfig) {
+	state := testWorkflowStateWithTemplateHash(hash)
+	raw := map[string]interface{}{
+		"template":                    "bar",
+		"hardwares":                   `{"device_1":"ff:ff:ff:ff:ff:ff"}`,
+		"recreate_on_template_change": true,
+	}
+
+	if triggers {
+		state.Attributes["triggers.%"] = "1"
+		state.Attributes["triggers.template"] = hash
+		raw["triggers"] = map[string]interface{}{"template": planned}
+	}
+
+	return state, terraform.NewResourceConfigRaw(raw)
+}
+
+func TestResourceWorkflowDiff_templateChangedInSamePlan(t *testing.T) {
+	t.Parallel()
+
+	oldContent := testAccTemplateContent(1)
+	newContent := testAccTemplateContent(2)
+
+	cases := []struct {
+		name        string
+		content     string
+		hash        string
+		triggers    bool
+		requiresNew bool
+	}{
+		{
+			// Template is not updated yet when planning, so the change is only
+			// detected by the next plan.
+			name:    "without_triggers",
+			content: oldContent,
+			hash:    templateHash(oldContent),
+		},
+		{
+			name:        "with_triggers",
+			content:     oldContent,
+			hash:        templateHash(oldContent),
+			triggers:    true,
+			requiresNew: true,
+		},
+		{
+			name:     "with_triggers_applied",
+			content:  newContent,
+			hash:     templateHash(newContent),
+			triggers: true,
+		},
+	}
+
+	r := Provider().ResourcesMap["tinkerbell_workflow"]
+
+	for _, c := range cases {
+		state, config := testWorkflowWithTemplateTrigger(c.hash, templateHash(newContent), c.triggers)
+		tc := testTinkClientConfig(&tinkClient{templateClient: testTemplateClientWithContent(c.content)})
+
+		diff, err := r.Diff(context.Background(), state, config, tc)
+		if err != nil {
+			t.Fatalf("%s: calculating diff: %v", c.name, err)
+		}
+
+		if got := diff.RequiresNew(); got != c.requiresNew {
+			t.Fatalf("%s: expected requires new to be %v, got %v, diff: %v", c.name, c.requiresNew, got, diff)
+		}
+
+		// Hash of the updated template is recorded when the workflow is created.
+		if c.requiresNew && !diff.Attributes["template_hash"].NewComputed {
+			t.Fatalf("%s: expected template hash to be computed, got diff: %v", c.name, diff)
+		}
+
+		if !c.requiresNew && !diff.Empty() {
+			t.Fatalf("%s: expected empty diff, got: %v", c.name, diff)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package tinkerbell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	serializationError = "could not serialize access due to read/write dependencies among transactions"
+
+	defaultMaxRetries      = 5
+	maxMaxRetries          = 100
+	defaultRetryBackoffMin = "1s"
+	defaultRetryBackoffMax = "30s"
+)
+
+// retryPolicy defines how requests failing with transient errors are retried.
+type retryPolicy struct {
+	maxRetries int
+	backoffMin time.Duration
+	backoffMax time.Duration
+}
+
+// do calls given function until it succeeds, returns error not accepted by retryable
+// function, number of retries is exhausted or given context is cancelled.
+func (rp *retryPolicy) do(ctx context.Context, retryable func(context.Context, error) bool, f func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= rp.maxRetries || !retryable(ctx, err) {
+			return err
+		}
+
+		backoff := rp.backoff(attempt)
+
+		log.Printf("[DEBUG] Retrying request after %s (attempt %d/%d): %v", backoff, attempt+1, rp.maxRetries, err)
+
+		t := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+
+			return fmt.Errorf("waiting for retry: %w, last error: %v", ctx.Err(), err)
+		case <-t.C:
+		}
+	}
+}
+
+// backoff returns exponentially growing duration for given attempt, limited by
+// backoffMax, with random jitter of up to half of the duration.
+func (rp *retryPolicy) backoff(attempt int) time.Duration {
+	backoff := rp.backoffMin
+
+	// Doubling stops at the limit, so the duration never overflows.
+	for i := 0; i < attempt && backoff < rp.backoffMax; i++ {
+		backoff *= 2
+	}
+
+	if backoff > rp.backoffMax {
+		backoff = rp.backoffMax
+	}
+
+	if backoff <= 1 {
+		return backoff
+	}
+
+	//nolint:gosec // Jitter does not need to be cryptographically secure.
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func validateMaxRetries(m interface{}, p cty.Path) diag.Diagnostics {
+	if v := m.(int); v < 0 || v > maxMaxRetries {
+		return diagsFromErr(fmt.Errorf("value must be between 0 and %d", maxMaxRetries))
+	}
+
+	return nil
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	// If parent context is done, retrying makes no sense.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if strings.Contains(err.Error(), serializationError) {
+		return true
+	}
+
+	//nolint:exhaustive // Only listed codes are retryable.
+	switch statusCode(err) {
+	case codes.Unavailable, codes.Aborted, codes.ResourceExhausted, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableWrite checks if request modifying server state can be safely retried after
+// given error. Unlike reads, such requests are only retried if the error proves that
+// the request was not processed, as e.g. creating a workflow twice is not harmless.
+func isRetryableWrite(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	// Transaction failing with serialization error is rolled back.
+	if strings.Contains(err.Error(), serializationError) {
+		return true
+	}
+
+	var nse *requestNotSentError
+
+	return errors.As(err, &nse)
+}
@@ -0,0 +1,251 @@
+package tinkerbell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err       error
+		retryable bool
+	}{
+		"unavailable": {
+			err:       status.Error(codes.Unavailable, "foo"),
+			retryable: true,
+		},
+		"aborted": {
+			err:       status.Error(codes.Aborted, "foo"),
+			retryable: true,
+		},
+		"resource_exhausted": {
+			err:       status.Error(codes.ResourceExhausted, "foo"),
+			retryable: true,
+		},
+		"deadline_exceeded_wrapped": {
+			err:       &timeoutError{method: "foo", err: status.Error(codes.DeadlineExceeded, "foo")},
+			retryable: true,
+		},
+		"serialization_error": {
+			err:       fmt.Errorf("receiving hardware entry: %w", status.Error(codes.Unknown, serializationError)),
+			retryable: true,
+		},
+		"not_found": {
+			err: status.Error(codes.NotFound, "foo"),
+		},
+		"invalid_argument": {
+			err: status.Error(codes.InvalidArgument, "foo"),
+		},
+		"non_grpc": {
+			err: errors.New("foo"),
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isRetryable(context.Background(), c.err); got != c.retryable {
+				t.Fatalf("Expected retryable to be %v, got %v", c.retryable, got)
+			}
+		})
+	}
+}
+
+func TestIsRetryableWrite(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err       error
+		retryable bool
+	}{
+		"not_sent": {
+			err:       fmt.Errorf("creating: %w", &requestNotSentError{err: status.Error(codes.Unavailable, "foo")}),
+			retryable: true,
+		},
+		"serialization_error": {
+			err:       status.Error(codes.Unknown, serializationError),
+			retryable: true,
+		},
+		"unavailable": {
+			err: status.Error(codes.Unavailable, "foo"),
+		},
+		"aborted": {
+			err: status.Error(codes.Aborted, "foo"),
+		},
+		"deadline_exceeded": {
+			err: &timeoutError{method: "foo", err: status.Error(codes.DeadlineExceeded, "foo")},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isRetryableWrite(context.Background(), c.err); got != c.retryable {
+				t.Fatalf("Expected retryable to be %v, got %v", c.retryable, got)
+			}
+		})
+	}
+}
+
+func TestIsRetryable_cancelledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if isRetryable(ctx, status.Error(codes.Unavailable, "foo")) {
+		t.Fatalf("Errors should not be retried when context is cancelled")
+	}
+}
+
+func testRetryPolicy() *retryPolicy {
+	return &retryPolicy{
+		maxRetries: 3,
+		backoffMin: time.Millisecond,
+		backoffMax: 5 * time.Millisecond,
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	err := testRetryPolicy().do(context.Background(), isRetryable, func() error {
+		calls++
+
+		if calls < 3 {
+			return status.Error(codes.Unavailable, "foo")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got: %v", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryPolicyDo_maxRetries(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	err := testRetryPolicy().do(context.Background(), isRetryable, func() error {
+		calls++
+
+		return status.Error(codes.Unavailable, "foo")
+	})
+	if err == nil {
+		t.Fatalf("Expected retry to fail")
+	}
+
+	if calls != 4 {
+		t.Fatalf("Expected 4 calls, got %d", calls)
+	}
+}
+
+func TestRetryPolicyDo_nonRetryable(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	err := testRetryPolicy().do(context.Background(), isRetryable, func() error {
+		calls++
+
+		return status.Error(codes.InvalidArgument, "foo")
+	})
+	if err == nil {
+		t.Fatalf("Expected retry to fail")
+	}
+
+	if calls != 1 {
+		t.Fatalf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryPolicyDo_contextCancelled(t *testing.T) {
+	t.Parallel()
+
+	rp := &retryPolicy{
+		maxRetries: 3,
+		backoffMin: time.Hour,
+		backoffMax: time.Hour,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := rp.do(ctx, isRetryable, func() error {
+		return status.Error(codes.Unavailable, "foo")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded error, got: %v", err)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	rp := &retryPolicy{
+		backoffMin: time.Second,
+		backoffMax: 10 * time.Second,
+	}
+
+	for attempt, max := range []time.Duration{1, 2, 4, 8, 10, 10, 10} {
+		max *= time.Second
+
+		b := rp.backoff(attempt)
+		if b < max/2 || b > max {
+			t.Fatalf("Expected backoff for attempt %d to be between %s and %s, got %s", attempt, max/2, max, b)
+		}
+	}
+
+	if b := rp.backoff(100); b > rp.backoffMax {
+		t.Fatalf("Expected backoff to be limited to %s, got %s", rp.backoffMax, b)
+	}
+}
+
+func TestRetryPolicyBackoff_largeMinimum(t *testing.T) {
+	t.Parallel()
+
+	rp := &retryPolicy{
+		backoffMin: 5 * time.Second,
+		backoffMax: time.Hour,
+	}
+
+	// Shifting the minimum overflows from attempt 31.
+	for attempt := 0; attempt <= maxMaxRetries; attempt++ {
+		if b := rp.backoff(attempt); b < rp.backoffMin/2 || b > rp.backoffMax {
+			t.Fatalf("Expected backoff for attempt %d to be between %s and %s, got %s",
+				attempt, rp.backoffMin/2, rp.backoffMax, b)
+		}
+	}
+}
+
+func TestValidateMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	for v, valid := range map[int]bool{-1: false, 0: true, 5: true, maxMaxRetries: true, maxMaxRetries + 1: false} {
+		if diags := validateMaxRetries(v, nil); diags.HasError() == valid {
+			t.Errorf("Expected validity of %d to be %v, got %v", v, valid, diags)
+		}
+	}
+}
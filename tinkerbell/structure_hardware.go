@@ -0,0 +1,609 @@
+package tinkerbell
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/protos/hardware"
+)
+
+const (
+	hardwareIDAttribute       = "hardware_id"
+	hardwareMetadataAttribute = "metadata"
+	hardwareNetworkAttribute  = "network"
+)
+
+// hardwareMetadata represents known fields of the JSON encoded hardware metadata.
+type hardwareMetadata struct {
+	Facility *hardwareFacility `json:"facility,omitempty"`
+	Instance json.RawMessage   `json:"instance,omitempty"`
+	State    string            `json:"state,omitempty"`
+}
+
+type hardwareFacility struct {
+	FacilityCode    string `json:"facility_code"`
+	PlanSlug        string `json:"plan_slug"`
+	PlanVersionSlug string `json:"plan_version_slug"`
+}
+
+// hardwareStructuredSchema returns schema of structured hardware attributes, mirroring
+// hardware.Hardware type.
+func hardwareStructuredSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		hardwareIDAttribute: {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{dataAttribute},
+			ExactlyOneOf:  []string{dataAttribute, hardwareIDAttribute},
+			Description:   "Hardware ID in UUID format.",
+		},
+		hardwareMetadataAttribute: hardwareMetadataSchema(),
+		hardwareNetworkAttribute:  hardwareNetworkSchema(),
+	}
+}
+
+// hardwareMetadataSchema returns schema of known fields of the hardware metadata. Other
+// fields of the metadata are only available in JSON formatted data.
+func hardwareMetadataSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		Computed:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{dataAttribute},
+		Description:   "Hardware metadata.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"facility": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"facility_code": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+							"plan_slug": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+							"plan_version_slug": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+				"instance": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					DiffSuppressFunc: suppressEquivalentJSONDiffs,
+					Description:      "JSON formatted instance metadata.",
+				},
+				"state": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// hardwareNetworkSchema returns schema of hardware network interfaces.
+func hardwareNetworkSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		Computed:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{dataAttribute},
+		Description:   "Hardware network configuration.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"interfaces": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"dhcp":    hardwareDHCPSchema(),
+							"netboot": hardwareNetbootSchema(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func hardwareDHCPSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"mac": {
+					Type:     schema.TypeString,
+					Required: true,
+					// Tink server stores MAC addresses in lowercase.
+					DiffSuppressFunc: suppressCaseDiffs,
+				},
+				"ip": hardwareDHCPIPSchema(),
+				"hostname": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"lease_time": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"name_servers": optionalStringList(),
+				"time_servers": optionalStringList(),
+				"arch": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"uefi": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"iface_name": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+func hardwareDHCPIPSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"address": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"netmask": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"gateway": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"family": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+func optionalStringList() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func hardwareNetbootSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"allow_pxe": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"allow_workflow": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"ipxe": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"url": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+							"contents": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+				"osie": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"base_url": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+							"kernel": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+							"initrd": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// firstElem returns first element of the list representing a block with MaxItems: 1.
+func firstElem(v interface{}) map[string]interface{} {
+	l, ok := v.([]interface{})
+	if !ok || len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m, _ := l[0].(map[string]interface{})
+
+	return m
+}
+
+func expandStringList(v interface{}) []string {
+	l, _ := v.([]interface{})
+	if len(l) == 0 {
+		return nil
+	}
+
+	s := make([]string, 0, len(l))
+
+	for _, e := range l {
+		str, _ := e.(string)
+		s = append(s, str)
+	}
+
+	return s
+}
+
+// expandHardware builds hardware entry from the structured attributes. Existing metadata
+// is the metadata of the entry stored on the server, which fields not covered by the
+// structured attributes are preserved from.
+func expandHardware(d *schema.ResourceData, existingMetadata string) (*hardware.Hardware, error) {
+	hw := &hardware.Hardware{
+		Id:      d.Get(hardwareIDAttribute).(string),
+		Network: expandHardwareNetwork(d.Get(hardwareNetworkAttribute)),
+	}
+
+	md, err := expandHardwareMetadata(d.Get(hardwareMetadataAttribute), existingMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("expanding %q: %w", hardwareMetadataAttribute, err)
+	}
+
+	hw.Metadata = md
+
+	return hw, nil
+}
+
+func expandHardwareMetadata(v interface{}, existing string) (string, error) {
+	m := firstElem(v)
+	if m == nil {
+		return existing, nil
+	}
+
+	known := hardwareMetadata{
+		State: m["state"].(string),
+	}
+
+	if f := firstElem(m["facility"]); f != nil {
+		known.Facility = &hardwareFacility{
+			FacilityCode:    f["facility_code"].(string),
+			PlanSlug:        f["plan_slug"].(string),
+			PlanVersionSlug: f["plan_version_slug"].(string),
+		}
+	}
+
+	if i := m["instance"].(string); i != "" {
+		if !json.Valid([]byte(i)) {
+			return "", fmt.Errorf("%q is not valid JSON", "instance")
+		}
+
+		known.Instance = json.RawMessage(i)
+	}
+
+	md, err := mergeHardwareMetadata(existing, known)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(md)
+	if err != nil {
+		return "", fmt.Errorf("serializing metadata: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// mergeHardwareMetadata returns existing metadata fields with known fields replaced by
+// given ones. Metadata which is not a JSON object can't be merged and is replaced.
+func mergeHardwareMetadata(existing string, known hardwareMetadata) (map[string]json.RawMessage, error) {
+	md := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal([]byte(existing), &md); err != nil || md == nil {
+		md = map[string]json.RawMessage{}
+	}
+
+	b, err := json.Marshal(known)
+	if err != nil {
+		return nil, fmt.Errorf("serializing metadata: %w", err)
+	}
+
+	fields := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("decoding metadata: %w", err)
+	}
+
+	for _, k := range []string{"facility", "instance", "state"} {
+		delete(md, k)
+
+		if v, ok := fields[k]; ok {
+			md[k] = v
+		}
+	}
+
+	return md, nil
+}
+
+func expandHardwareNetwork(v interface{}) *hardware.Hardware_Network {
+	m := firstElem(v)
+	if m == nil {
+		return nil
+	}
+
+	network := &hardware.Hardware_Network{}
+
+	interfaces, _ := m["interfaces"].([]interface{})
+
+	for _, i := range interfaces {
+		im, _ := i.(map[string]interface{})
+
+		network.Interfaces = append(network.Interfaces, &hardware.Hardware_Network_Interface{
+			Dhcp:    expandHardwareDHCP(im["dhcp"]),
+			Netboot: expandHardwareNetboot(im["netboot"]),
+		})
+	}
+
+	return network
+}
+
+func expandHardwareDHCP(v interface{}) *hardware.Hardware_DHCP {
+	m := firstElem(v)
+	if m == nil {
+		return nil
+	}
+
+	dhcp := &hardware.Hardware_DHCP{
+		Mac:         m["mac"].(string),
+		Hostname:    m["hostname"].(string),
+		LeaseTime:   int64(m["lease_time"].(int)),
+		NameServers: expandStringList(m["name_servers"]),
+		TimeServers: expandStringList(m["time_servers"]),
+		Arch:        m["arch"].(string),
+		Uefi:        m["uefi"].(bool),
+		IfaceName:   m["iface_name"].(string),
+	}
+
+	if ip := firstElem(m["ip"]); ip != nil {
+		dhcp.Ip = &hardware.Hardware_DHCP_IP{
+			Address: ip["address"].(string),
+			Netmask: ip["netmask"].(string),
+			Gateway: ip["gateway"].(string),
+			Family:  int64(ip["family"].(int)),
+		}
+	}
+
+	return dhcp
+}
+
+func expandHardwareNetboot(v interface{}) *hardware.Hardware_Netboot {
+	m := firstElem(v)
+	if m == nil {
+		return nil
+	}
+
+	netboot := &hardware.Hardware_Netboot{
+		AllowPxe:      m["allow_pxe"].(bool),
+		AllowWorkflow: m["allow_workflow"].(bool),
+	}
+
+	if ipxe := firstElem(m["ipxe"]); ipxe != nil {
+		netboot.Ipxe = &hardware.Hardware_Netboot_IPXE{
+			Url:      ipxe["url"].(string),
+			Contents: ipxe["contents"].(string),
+		}
+	}
+
+	if osie := firstElem(m["osie"]); osie != nil {
+		netboot.Osie = &hardware.Hardware_Netboot_Osie{
+			BaseUrl: osie["base_url"].(string),
+			Kernel:  osie["kernel"].(string),
+			Initrd:  osie["initrd"].(string),
+		}
+	}
+
+	return netboot
+}
+
+// decodeHardwareMetadata decodes known fields of JSON encoded hardware metadata. Metadata
+// is free-form, so false is returned if it does not have the expected structure.
+func decodeHardwareMetadata(metadata string) (hardwareMetadata, bool) {
+	md := hardwareMetadata{}
+
+	if err := json.Unmarshal([]byte(metadata), &md); err != nil {
+		return hardwareMetadata{}, false
+	}
+
+	return md, true
+}
+
+// flattenHardwareMetadata converts JSON encoded hardware metadata into the structured
+// attribute value. Metadata without the expected structure is only available in JSON
+// formatted data, so the attribute is left empty.
+func flattenHardwareMetadata(metadata string) []interface{} {
+	md, ok := decodeHardwareMetadata(metadata)
+	if !ok {
+		return []interface{}{}
+	}
+
+	m := map[string]interface{}{
+		"facility": []interface{}{},
+		"instance": "",
+		"state":    md.State,
+	}
+
+	if md.Facility != nil {
+		m["facility"] = []interface{}{
+			map[string]interface{}{
+				"facility_code":     md.Facility.FacilityCode,
+				"plan_slug":         md.Facility.PlanSlug,
+				"plan_version_slug": md.Facility.PlanVersionSlug,
+			},
+		}
+	}
+
+	if len(md.Instance) > 0 {
+		m["instance"] = string(md.Instance)
+	}
+
+	return []interface{}{m}
+}
+
+func flattenHardwareNetwork(network *hardware.Hardware_Network) []interface{} {
+	if network == nil {
+		return []interface{}{}
+	}
+
+	interfaces := make([]interface{}, 0, len(network.GetInterfaces()))
+
+	for _, i := range network.GetInterfaces() {
+		interfaces = append(interfaces, map[string]interface{}{
+			"dhcp":    flattenHardwareDHCP(i.GetDhcp()),
+			"netboot": flattenHardwareNetboot(i.GetNetboot()),
+		})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"interfaces": interfaces,
+		},
+	}
+}
+
+func flattenStringList(l []string) []interface{} {
+	r := make([]interface{}, 0, len(l))
+
+	for _, s := range l {
+		r = append(r, s)
+	}
+
+	return r
+}
+
+func flattenHardwareDHCP(dhcp *hardware.Hardware_DHCP) []interface{} {
+	if dhcp == nil {
+		return []interface{}{}
+	}
+
+	m := map[string]interface{}{
+		"mac":          dhcp.GetMac(),
+		"ip":           []interface{}{},
+		"hostname":     dhcp.GetHostname(),
+		"lease_time":   int(dhcp.GetLeaseTime()),
+		"name_servers": flattenStringList(dhcp.GetNameServers()),
+		"time_servers": flattenStringList(dhcp.GetTimeServers()),
+		"arch":         dhcp.GetArch(),
+		"uefi":         dhcp.GetUefi(),
+		"iface_name":   dhcp.GetIfaceName(),
+	}
+
+	if ip := dhcp.GetIp(); ip != nil {
+		m["ip"] = []interface{}{
+			map[string]interface{}{
+				"address": ip.GetAddress(),
+				"netmask": ip.GetNetmask(),
+				"gateway": ip.GetGateway(),
+				"family":  int(ip.GetFamily()),
+			},
+		}
+	}
+
+	return []interface{}{m}
+}
+
+func flattenHardwareNetboot(netboot *hardware.Hardware_Netboot) []interface{} {
+	if netboot == nil {
+		return []interface{}{}
+	}
+
+	m := map[string]interface{}{
+		"allow_pxe":      netboot.GetAllowPxe(),
+		"allow_workflow": netboot.GetAllowWorkflow(),
+		"ipxe":           []interface{}{},
+		"osie":           []interface{}{},
+	}
+
+	if ipxe := netboot.GetIpxe(); ipxe != nil {
+		m["ipxe"] = []interface{}{
+			map[string]interface{}{
+				"url":      ipxe.GetUrl(),
+				"contents": ipxe.GetContents(),
+			},
+		}
+	}
+
+	if osie := netboot.GetOsie(); osie != nil {
+		m["osie"] = []interface{}{
+			map[string]interface{}{
+				"base_url": osie.GetBaseUrl(),
+				"kernel":   osie.GetKernel(),
+				"initrd":   osie.GetInitrd(),
+			},
+		}
+	}
+
+	return []interface{}{m}
+}
+
+// flattenHardware returns values of structured attributes for given hardware entry.
+func flattenHardware(hw *hardware.Hardware) map[string]interface{} {
+	return map[string]interface{}{
+		hardwareIDAttribute:       hw.GetId(),
+		hardwareMetadataAttribute: flattenHardwareMetadata(hw.GetMetadata()),
+		hardwareNetworkAttribute:  flattenHardwareNetwork(hw.GetNetwork()),
+	}
+}
+
+// suppressCaseDiffs suppresses diffs of values differing only in letter case.
+func suppressCaseDiffs(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
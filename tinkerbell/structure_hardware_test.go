@@ -0,0 +1,212 @@
+package tinkerbell
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/tinkerbell/tink/pkg"
+	"github.com/tinkerbell/tink/protos/hardware"
+)
+
+const testHardwareMetadata = `{"facility":{"facility_code":"ewr1","plan_slug":"c2.medium.x86",` +
+	`"plan_version_slug":""},"instance":{"id":"bar"},"state":"provisioning"}`
+
+func testHardware() *hardware.Hardware {
+	return &hardware.Hardware{
+		Id:       "foo",
+		Metadata: testHardwareMetadata,
+		Network: &hardware.Hardware_Network{
+			Interfaces: []*hardware.Hardware_Network_Interface{
+				{
+					Dhcp: &hardware.Hardware_DHCP{
+						Mac:         "ff:ff:ff:ff:ff:ff",
+						Hostname:    "baz",
+						LeaseTime:   86400,
+						NameServers: []string{"1.1.1.1"},
+						Arch:        "x86_64",
+						Uefi:        true,
+						Ip: &hardware.Hardware_DHCP_IP{
+							Address: "192.168.1.5",
+							Netmask: "255.255.255.248",
+							Gateway: "192.168.1.1",
+						},
+					},
+					Netboot: &hardware.Hardware_Netboot{
+						AllowPxe:      true,
+						AllowWorkflow: true,
+						Ipxe: &hardware.Hardware_Netboot_IPXE{
+							Url: "http://example.com/boot.ipxe",
+						},
+						Osie: &hardware.Hardware_Netboot_Osie{
+							Kernel: "vmlinuz",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFlattenExpandHardware(t *testing.T) {
+	t.Parallel()
+
+	hw := testHardware()
+
+	attrs := flattenHardware(hw)
+
+	d := schema.TestResourceDataRaw(t, hardwareStructuredSchema(), map[string]interface{}{})
+
+	for k, v := range attrs {
+		if err := d.Set(k, v); err != nil {
+			t.Fatalf("Setting %q: %v", k, err)
+		}
+	}
+
+	got, err := expandHardware(d, "")
+	if err != nil {
+		t.Fatalf("Expanding hardware: %v", err)
+	}
+
+	expected, err := json.Marshal(pkg.HardwareWrapper{Hardware: hw})
+	if err != nil {
+		t.Fatalf("Serializing expected hardware: %v", err)
+	}
+
+	actual, err := json.Marshal(pkg.HardwareWrapper{Hardware: got})
+	if err != nil {
+		t.Fatalf("Serializing expanded hardware: %v", err)
+	}
+
+	if !jsonBytesEqual(expected, actual) {
+		t.Fatalf("Expected expanded hardware to be %s, got %s", expected, actual)
+	}
+}
+
+func TestExpandHardwareMetadata_invalidInstance(t *testing.T) {
+	t.Parallel()
+
+	md := []interface{}{
+		map[string]interface{}{
+			"facility": []interface{}{},
+			"instance": "bad json",
+			"state":    "",
+		},
+	}
+
+	if _, err := expandHardwareMetadata(md, ""); err == nil {
+		t.Fatalf("Expanding metadata with malformed instance should fail")
+	}
+}
+
+func TestExpandHardwareMetadata_merge(t *testing.T) {
+	t.Parallel()
+
+	md := []interface{}{
+		map[string]interface{}{
+			"facility": []interface{}{},
+			"instance": `{"id":"bar"}`,
+			"state":    "in_use",
+		},
+	}
+
+	cases := map[string]struct {
+		existing string
+		expected string
+	}{
+		"none": {
+			existing: "",
+			expected: `{"instance":{"id":"bar"},"state":"in_use"}`,
+		},
+		"unknown_fields": {
+			existing: `{"custom":{"foo":1},"facility":{"facility_code":"ewr1"},"state":"provisioning"}`,
+			expected: `{"custom":{"foo":1},"instance":{"id":"bar"},"state":"in_use"}`,
+		},
+		"not_object": {
+			existing: `"foo"`,
+			expected: `{"instance":{"id":"bar"},"state":"in_use"}`,
+		},
+		"null": {
+			existing: `null`,
+			expected: `{"instance":{"id":"bar"},"state":"in_use"}`,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := expandHardwareMetadata(md, c.existing)
+			if err != nil {
+				t.Fatalf("Expanding metadata: %v", err)
+			}
+
+			if got != c.expected {
+				t.Fatalf("Expected metadata %s, got %s", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestExpandHardwareMetadata_notSet(t *testing.T) {
+	t.Parallel()
+
+	existing := `{"custom":"foo"}`
+
+	got, err := expandHardwareMetadata([]interface{}{}, existing)
+	if err != nil {
+		t.Fatalf("Expanding metadata: %v", err)
+	}
+
+	if got != existing {
+		t.Fatalf("Expected existing metadata %s to be preserved, got %s", existing, got)
+	}
+}
+
+func TestFlattenHardwareMetadata_unexpectedStructure(t *testing.T) {
+	t.Parallel()
+
+	for name, md := range map[string]string{
+		"empty":           "",
+		"string":          `"foo"`,
+		"array":           `[1, 2]`,
+		"facility_string": `{"facility":"ewr1"}`,
+	} {
+		md := md
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := flattenHardwareMetadata(md); len(got) != 0 {
+				t.Fatalf("Expected empty metadata, got %v", got)
+			}
+		})
+	}
+}
+
+func TestResourceHardwareDiff_macCase(t *testing.T) {
+	t.Parallel()
+
+	d := schema.TestResourceDataRaw(t, resourceHardwareSchema(), flattenHardware(testHardware()))
+	d.SetId("foo")
+
+	for mac, changed := range map[string]bool{"FF:FF:FF:FF:FF:FF": false, "00:00:00:00:00:01": true} {
+		hw := testHardware()
+		hw.Network.Interfaces[0].Dhcp.Mac = mac
+
+		config := terraform.NewResourceConfigRaw(flattenHardware(hw))
+
+		diff, err := resourceHardware().Diff(context.Background(), d.State(), config, nil)
+		if err != nil {
+			t.Fatalf("Calculating diff: %v", err)
+		}
+
+		if got := diff != nil && len(diff.Attributes) > 0; got != changed {
+			t.Fatalf("Expected diff for MAC address %q to be %v, got: %v", mac, changed, diff)
+		}
+	}
+}
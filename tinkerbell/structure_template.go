@@ -0,0 +1,122 @@
+package tinkerbell
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tinkerbell/tink/workflow"
+)
+
+// templateTasksSchema returns schema of tasks parsed from the template content,
+// mirroring workflow.Task type.
+func templateTasksSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Tasks parsed from the template content.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"worker": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"volumes":     computedStringList(),
+				"environment": computedStringMap(),
+				"actions": {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Computed: true,
+							},
+							"image": {
+								Type:     schema.TypeString,
+								Computed: true,
+							},
+							"timeout": {
+								Type:     schema.TypeInt,
+								Computed: true,
+							},
+							"command":     computedStringList(),
+							"on_timeout":  computedStringList(),
+							"on_failure":  computedStringList(),
+							"volumes":     computedStringList(),
+							"environment": computedStringMap(),
+							"pid": {
+								Type:     schema.TypeString,
+								Computed: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func computedStringList() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func computedStringMap() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func flattenStringMap(m map[string]string) map[string]interface{} {
+	r := map[string]interface{}{}
+
+	for k, v := range m {
+		r[k] = v
+	}
+
+	return r
+}
+
+// flattenTemplateTasks converts tasks of parsed template into 'tasks' attribute value.
+func flattenTemplateTasks(w *workflow.Workflow) []interface{} {
+	tasks := make([]interface{}, 0, len(w.Tasks))
+
+	for _, t := range w.Tasks {
+		actions := make([]interface{}, 0, len(t.Actions))
+
+		for _, a := range t.Actions {
+			actions = append(actions, map[string]interface{}{
+				"name":        a.Name,
+				"image":       a.Image,
+				"timeout":     int(a.Timeout),
+				"command":     flattenStringList(a.Command),
+				"on_timeout":  flattenStringList(a.OnTimeout),
+				"on_failure":  flattenStringList(a.OnFailure),
+				"volumes":     flattenStringList(a.Volumes),
+				"environment": flattenStringMap(a.Environment),
+				"pid":         a.Pid,
+			})
+		}
+
+		tasks = append(tasks, map[string]interface{}{
+			"name":        t.Name,
+			"worker":      t.WorkerAddr,
+			"volumes":     flattenStringList(t.Volumes),
+			"environment": flattenStringMap(t.Environment),
+			"actions":     actions,
+		})
+	}
+
+	return tasks
+}
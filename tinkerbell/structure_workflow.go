@@ -0,0 +1,148 @@
+package tinkerbell
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+const (
+	hardwaresAttribute   = "hardwares"
+	hardwareMapAttribute = "hardware_map"
+
+	// unknownValue is used by Terraform SDK for map values not known during planning.
+	unknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+)
+
+// deviceReferenceRegexp matches references to devices in the template, e.g. {{.device_1}}.
+var deviceReferenceRegexp = regexp.MustCompile(`{{-?\s*\.([A-Za-z0-9_]+)\s*-?}}`)
+
+func validateHardwareMap(m interface{}, p cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for device, v := range m.(map[string]interface{}) {
+		addr, _ := v.(string)
+
+		if addr == unknownValue {
+			continue
+		}
+
+		if err := validateDeviceAddress(addr); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Invalid address of device %q", device),
+				Detail:        err.Error(),
+				AttributePath: append(p, cty.IndexStep{Key: cty.StringVal(device)}),
+			})
+		}
+	}
+
+	return diags
+}
+
+// validateDeviceAddress checks if given address is a valid MAC or IP address or
+// hardware ID.
+func validateDeviceAddress(addr string) error {
+	if _, err := net.ParseMAC(addr); err == nil {
+		return nil
+	}
+
+	if net.ParseIP(addr) != nil {
+		return nil
+	}
+
+	if isHardwareID(addr) {
+		return nil
+	}
+
+	return fmt.Errorf("%q is neither valid MAC nor IP address nor hardware ID", addr)
+}
+
+// isHardwareID checks if given device address is a hardware ID in UUID format.
+func isHardwareID(addr string) bool {
+	_, err := uuid.Parse(addr)
+
+	return err == nil
+}
+
+// expandHardwareMap converts 'hardware_map' attribute value into JSON format expected
+// by Tink server.
+func expandHardwareMap(m map[string]interface{}) (string, error) {
+	devices := map[string]string{}
+
+	for device, v := range m {
+		devices[device], _ = v.(string)
+	}
+
+	b, err := json.Marshal(devices)
+	if err != nil {
+		return "", fmt.Errorf("serializing hardware map: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// flattenHardwareMap converts JSON formatted hardwares into 'hardware_map' attribute value.
+func flattenHardwareMap(hardwares string) (map[string]interface{}, error) {
+	devices := map[string]interface{}{}
+
+	if hardwares == "" {
+		return devices, nil
+	}
+
+	if err := json.Unmarshal([]byte(hardwares), &devices); err != nil {
+		return nil, fmt.Errorf("decoding hardwares: %w", err)
+	}
+
+	for device, v := range devices {
+		if _, ok := v.(string); !ok {
+			devices[device] = fmt.Sprint(v)
+		}
+	}
+
+	return devices, nil
+}
+
+// templateDeviceReferences returns sorted names of devices referenced in given template.
+func templateDeviceReferences(content string) []string {
+	seen := map[string]struct{}{}
+
+	for _, m := range deviceReferenceRegexp.FindAllStringSubmatch(content, -1) {
+		seen[m[1]] = struct{}{}
+	}
+
+	devices := make([]string, 0, len(seen))
+
+	for device := range seen {
+		devices = append(devices, device)
+	}
+
+	sort.Strings(devices)
+
+	return devices
+}
+
+// validateDeviceReferences checks if all devices referenced in given template are
+// defined in given devices map.
+func validateDeviceReferences(content string, devices map[string]interface{}) error {
+	var missing []string
+
+	for _, device := range templateDeviceReferences(content) {
+		if _, ok := devices[device]; !ok {
+			missing = append(missing, device)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("devices referenced in the template are not defined: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
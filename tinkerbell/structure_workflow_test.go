@@ -0,0 +1,113 @@
+package tinkerbell
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestValidateHardwareMap(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		devices map[string]interface{}
+		valid   bool
+	}{
+		"mac": {
+			devices: map[string]interface{}{"device_1": "ff:ff:ff:ff:ff:ff"},
+			valid:   true,
+		},
+		"ipv4": {
+			devices: map[string]interface{}{"device_1": "192.168.1.5"},
+			valid:   true,
+		},
+		"ipv6": {
+			devices: map[string]interface{}{"device_1": "fd00::5"},
+			valid:   true,
+		},
+		"hardware_id": {
+			devices: map[string]interface{}{"device_1": "2bd4b2b3-3104-4f67-8b5c-3d208d9cd1cd"},
+			valid:   true,
+		},
+		"unknown": {
+			devices: map[string]interface{}{"device_1": unknownValue},
+			valid:   true,
+		},
+		"malformed": {
+			devices: map[string]interface{}{"device_1": "ff:ff:ff:ff:ff"},
+		},
+		"empty": {
+			devices: map[string]interface{}{"device_1": ""},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if diags := validateHardwareMap(c.devices, cty.Path{}); diags.HasError() == c.valid {
+				t.Fatalf("Expected valid to be %v, got diagnostics: %v", c.valid, diags)
+			}
+		})
+	}
+}
+
+func TestTemplateDeviceReferences(t *testing.T) {
+	t.Parallel()
+
+	content := `
+tasks:
+  - name: "os-installation"
+    worker: "{{.device_1}}"
+  - name: "post-installation"
+    worker: "{{ .device_2 }}"
+  - name: "cleanup"
+    worker: "{{.device_1}}"
+`
+
+	expected := []string{"device_1", "device_2"}
+
+	if got := templateDeviceReferences(content); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected references %v, got %v", expected, got)
+	}
+}
+
+func TestValidateDeviceReferences(t *testing.T) {
+	t.Parallel()
+
+	content := `worker: "{{.device_1}}"`
+
+	if err := validateDeviceReferences(content, map[string]interface{}{"device_1": "ff:ff:ff:ff:ff:ff"}); err != nil {
+		t.Fatalf("Expected defined devices to be valid, got: %v", err)
+	}
+
+	if err := validateDeviceReferences(content, map[string]interface{}{"device_2": "ff:ff:ff:ff:ff:ff"}); err == nil {
+		t.Fatalf("Expected missing device to be invalid")
+	}
+}
+
+func TestExpandFlattenHardwareMap(t *testing.T) {
+	t.Parallel()
+
+	devices := map[string]interface{}{
+		"device_1": "ff:ff:ff:ff:ff:ff",
+		"device_2": "192.168.1.5",
+	}
+
+	hardwares, err := expandHardwareMap(devices)
+	if err != nil {
+		t.Fatalf("Expanding hardware map: %v", err)
+	}
+
+	got, err := flattenHardwareMap(hardwares)
+	if err != nil {
+		t.Fatalf("Flattening hardware map: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, devices) {
+		t.Fatalf("Expected %v, got %v", devices, got)
+	}
+}
@@ -0,0 +1,75 @@
+package tinkerbell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/tinkerbell/tink/protos/workflow"
+)
+
+const workflowDataAttribute = "workflow_data"
+
+// workflowDataMetadata is stored along with workflow data pushed by the provider,
+// similar to metadata stored by Tink workers.
+type workflowDataMetadata struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func validateJSON(m interface{}, p cty.Path) diag.Diagnostics {
+	if !json.Valid([]byte(m.(string))) {
+		return diagsFromErr(fmt.Errorf("value must be valid JSON"))
+	}
+
+	return nil
+}
+
+// updateWorkflowData stores given data as a new version of ephemeral data of the
+// workflow with given ID.
+func updateWorkflowData(ctx context.Context, tc *tinkClient, id, data string) error {
+	metadata, err := json.Marshal(workflowDataMetadata{
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("serializing workflow data metadata: %w", err)
+	}
+
+	req := &workflow.UpdateWorkflowDataRequest{
+		WorkflowId: id,
+		Metadata:   metadata,
+		Data:       []byte(data),
+	}
+
+	if err := tc.write(ctx, func() error {
+		_, err := tc.workflowClient.UpdateWorkflowData(ctx, req)
+
+		return err //nolint:wrapcheck
+	}); err != nil {
+		return fmt.Errorf("updating workflow %q data: %w", id, err)
+	}
+
+	return nil
+}
+
+// getWorkflowDataVersion returns the latest version of ephemeral data of the workflow
+// with given ID or 0 if no data has been stored yet.
+func getWorkflowDataVersion(ctx context.Context, tc *tinkClient, id string) (int, error) {
+	var res *workflow.GetWorkflowDataResponse
+
+	if err := tc.retry(ctx, func() error {
+		var err error
+
+		res, err = tc.workflowClient.GetWorkflowDataVersion(ctx, &workflow.GetWorkflowDataRequest{
+			WorkflowId: id,
+		})
+
+		return err //nolint:wrapcheck
+	}); err != nil {
+		return 0, fmt.Errorf("getting workflow %q data version: %w", id, err)
+	}
+
+	return int(res.GetVersion()), nil
+}
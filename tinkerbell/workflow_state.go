@@ -0,0 +1,183 @@
+package tinkerbell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/tinkerbell/tink/protos/workflow"
+)
+
+const (
+	defaultWorkflowPollInterval = "15s"
+
+	workflowStateSuccess = "SUCCESS"
+)
+
+// workflowState returns state of the whole workflow based on its context. Successful
+// action means successful workflow only if it's the last one.
+func workflowState(wc *workflow.WorkflowContext) workflow.State {
+	state := wc.GetCurrentActionState()
+
+	if state == workflow.State_STATE_SUCCESS && wc.GetCurrentActionIndex() != wc.GetTotalNumberOfActions()-1 {
+		return workflow.State_STATE_RUNNING
+	}
+
+	return state
+}
+
+// workflowStateName returns name of given state without the enum prefix, e.g. SUCCESS.
+func workflowStateName(state workflow.State) string {
+	return strings.TrimPrefix(state.String(), "STATE_")
+}
+
+func validateWorkflowWaitState(m interface{}, p cty.Path) diag.Diagnostics {
+	if s := m.(string); s != workflowStateSuccess {
+		return diagsFromErr(fmt.Errorf("unsupported state %q, expected %q", s, workflowStateSuccess))
+	}
+
+	return nil
+}
+
+// waitForWorkflow polls context of the workflow with given ID until the workflow succeeds,
+// fails or given context is done.
+func waitForWorkflow(ctx context.Context, tc *tinkClient, id string, interval time.Duration) error {
+	req := &workflow.GetRequest{
+		Id: id,
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		var wc *workflow.WorkflowContext
+
+		err := tc.retry(ctx, func() error {
+			var err error
+
+			wc, err = tc.workflowClient.GetWorkflowContext(ctx, req)
+
+			return err //nolint:wrapcheck
+		})
+
+		// Context may not be available right after the workflow is created.
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("getting workflow context: %w", err)
+		}
+
+		if err == nil {
+			state := workflowState(wc)
+
+			log.Printf("[DEBUG] Workflow %q is in state %s, task %q, action %q",
+				id, workflowStateName(state), wc.GetCurrentTask(), wc.GetCurrentAction())
+
+			//nolint:exhaustive // Other states mean workflow is still in progress.
+			switch state {
+			case workflow.State_STATE_SUCCESS:
+				return nil
+			case workflow.State_STATE_FAILED, workflow.State_STATE_TIMEOUT:
+				return workflowFailedError(ctx, tc, wc, state)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for workflow %q to reach state %s: %w", id, workflowStateSuccess, ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// workflowFailedError builds error describing failed workflow, including the last
+// event reported by the workflow if it can be fetched.
+func workflowFailedError(
+	ctx context.Context,
+	tc *tinkClient,
+	wc *workflow.WorkflowContext,
+	state workflow.State,
+) error {
+	err := fmt.Errorf("workflow %q reached state %s in task %q, action %q",
+		wc.GetWorkflowId(), workflowStateName(state), wc.GetCurrentTask(), wc.GetCurrentAction())
+
+	var events []*workflow.WorkflowActionStatus
+
+	if eventsErr := tc.retry(ctx, func() error {
+		var err error
+
+		events, err = getWorkflowEvents(ctx, tc.workflowClient, wc.GetWorkflowId())
+
+		return err
+	}); eventsErr != nil {
+		log.Printf("[WARN] Getting events of failed workflow %q: %v", wc.GetWorkflowId(), eventsErr)
+
+		return err
+	}
+
+	if len(events) == 0 {
+		return err
+	}
+
+	e := events[len(events)-1]
+
+	return fmt.Errorf("%w, last event: action %q on worker %q: %s: %s",
+		err, e.GetActionName(), e.GetWorkerId(), workflowStateName(e.GetActionStatus()), e.GetMessage())
+}
+
+// getWorkflowEvents returns all events reported by the workflow with given ID, oldest first.
+func getWorkflowEvents(
+	ctx context.Context,
+	c workflow.WorkflowServiceClient,
+	id string,
+) ([]*workflow.WorkflowActionStatus, error) {
+	list, err := c.ShowWorkflowEvents(ctx, &workflow.GetRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("getting workflow events: %w", err)
+	}
+
+	var events []*workflow.WorkflowActionStatus
+
+	for {
+		e, err := list.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("receiving workflow event: %w", err)
+		}
+
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// flattenWorkflowEvents converts given events into 'events' attribute value, keeping
+// only given number of most recent events if limit is positive.
+func flattenWorkflowEvents(events []*workflow.WorkflowActionStatus, limit int) []interface{} {
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+
+	r := make([]interface{}, 0, len(events))
+
+	for _, e := range events {
+		r = append(r, map[string]interface{}{
+			"task_name":   e.GetTaskName(),
+			"action_name": e.GetActionName(),
+			"status":      workflowStateName(e.GetActionStatus()),
+			"seconds":     int(e.GetSeconds()),
+			"message":     e.GetMessage(),
+			"worker_id":   e.GetWorkerId(),
+			"created_at":  formatTimestamp(e.GetCreatedAt()),
+		})
+	}
+
+	return r
+}
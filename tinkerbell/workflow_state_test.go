@@ -0,0 +1,211 @@
+package tinkerbell
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinkerbell/tink/protos/workflow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testWorkflowContext returns context of a workflow with 2 actions, with action of given
+// index being in given state.
+func testWorkflowContext(state workflow.State, index int64) *workflow.WorkflowContext {
+	return &workflow.WorkflowContext{
+		CurrentActionState:   state,
+		CurrentActionIndex:   index,
+		TotalNumberOfActions: 2,
+	}
+}
+
+func TestWorkflowState(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		wc       *workflow.WorkflowContext
+		expected workflow.State
+	}{
+		"pending": {
+			wc:       testWorkflowContext(workflow.State_STATE_PENDING, 0),
+			expected: workflow.State_STATE_PENDING,
+		},
+		"action_succeeded": {
+			wc:       testWorkflowContext(workflow.State_STATE_SUCCESS, 0),
+			expected: workflow.State_STATE_RUNNING,
+		},
+		"last_action_succeeded": {
+			wc:       testWorkflowContext(workflow.State_STATE_SUCCESS, 1),
+			expected: workflow.State_STATE_SUCCESS,
+		},
+		"failed": {
+			wc:       testWorkflowContext(workflow.State_STATE_FAILED, 0),
+			expected: workflow.State_STATE_FAILED,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := workflowState(c.wc); got != c.expected {
+				t.Fatalf("Expected state %s, got %s", c.expected, got)
+			}
+		})
+	}
+}
+
+// testWorkflowContexts returns function returning given contexts one by one, repeating
+// the last one.
+func testWorkflowContexts(
+	contexts ...*workflow.WorkflowContext,
+) func(context.Context, *workflow.GetRequest, ...grpc.CallOption) (*workflow.WorkflowContext, error) {
+	return func(ctx context.Context, in *workflow.GetRequest, opts ...grpc.CallOption) (*workflow.WorkflowContext, error) {
+		wc := contexts[0]
+
+		if len(contexts) > 1 {
+			contexts = contexts[1:]
+		}
+
+		if wc == nil {
+			return nil, status.Error(codes.NotFound, "not found")
+		}
+
+		return wc, nil
+	}
+}
+
+func TestWaitForWorkflow(t *testing.T) {
+	t.Parallel()
+
+	tc := &tinkClient{
+		retryPolicy: testRetryPolicy(),
+		workflowClient: &workflow.WorkflowServiceClientMock{
+			GetWorkflowContextFunc: testWorkflowContexts(
+				nil,
+				testWorkflowContext(workflow.State_STATE_RUNNING, 0),
+				testWorkflowContext(workflow.State_STATE_SUCCESS, 1),
+			),
+		},
+	}
+
+	if err := waitForWorkflow(context.Background(), tc, "foo", time.Millisecond); err != nil {
+		t.Fatalf("Waiting for workflow: %v", err)
+	}
+}
+
+func TestWaitForWorkflow_failed(t *testing.T) {
+	t.Parallel()
+
+	events := []*workflow.WorkflowActionStatus{
+		{ActionName: "disk-wipe", ActionStatus: workflow.State_STATE_SUCCESS},
+		{
+			ActionName:   "install-root-fs",
+			WorkerId:     "bar",
+			ActionStatus: workflow.State_STATE_FAILED,
+			Message:      "image not found",
+		},
+	}
+
+	tc := &tinkClient{
+		retryPolicy: testRetryPolicy(),
+		workflowClient: &workflow.WorkflowServiceClientMock{
+			GetWorkflowContextFunc: testWorkflowContexts(&workflow.WorkflowContext{
+				WorkflowId:           "foo",
+				CurrentTask:          "os-installation",
+				CurrentAction:        "install-root-fs",
+				CurrentActionState:   workflow.State_STATE_FAILED,
+				TotalNumberOfActions: 2,
+			}),
+			ShowWorkflowEventsFunc: func(
+				ctx context.Context,
+				in *workflow.GetRequest,
+				opts ...grpc.CallOption,
+			) (workflow.WorkflowService_ShowWorkflowEventsClient, error) {
+				return &testShowWorkflowEventsClient{events: events}, nil
+			},
+		},
+	}
+
+	err := waitForWorkflow(context.Background(), tc, "foo", time.Millisecond)
+	if err == nil {
+		t.Fatalf("Expected waiting for failed workflow to fail")
+	}
+
+	for _, s := range []string{"FAILED", "os-installation", "install-root-fs", "image not found"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("Expected error to contain %q, got: %v", s, err)
+		}
+	}
+}
+
+func TestWaitForWorkflow_contextDone(t *testing.T) {
+	t.Parallel()
+
+	tc := &tinkClient{
+		retryPolicy: testRetryPolicy(),
+		workflowClient: &workflow.WorkflowServiceClientMock{
+			GetWorkflowContextFunc: testWorkflowContexts(&workflow.WorkflowContext{
+				CurrentActionState:   workflow.State_STATE_RUNNING,
+				TotalNumberOfActions: 2,
+			}),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := waitForWorkflow(ctx, tc, "foo", time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded error, got: %v", err)
+	}
+}
+
+// testShowWorkflowEventsClient streams given events. Only Recv method is implemented.
+type testShowWorkflowEventsClient struct {
+	grpc.ClientStream
+
+	events []*workflow.WorkflowActionStatus
+}
+
+func (c *testShowWorkflowEventsClient) Recv() (*workflow.WorkflowActionStatus, error) {
+	if len(c.events) == 0 {
+		return nil, io.EOF
+	}
+
+	e := c.events[0]
+	c.events = c.events[1:]
+
+	return e, nil
+}
+
+func TestFlattenWorkflowEvents_limit(t *testing.T) {
+	t.Parallel()
+
+	events := []*workflow.WorkflowActionStatus{
+		{ActionName: "foo"},
+		{ActionName: "bar"},
+		{ActionName: "baz", ActionStatus: workflow.State_STATE_FAILED},
+	}
+
+	if got := flattenWorkflowEvents(events, 0); len(got) != 3 {
+		t.Fatalf("Expected all events without limit, got %d", len(got))
+	}
+
+	got := flattenWorkflowEvents(events, 2)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(got))
+	}
+
+	last := got[1].(map[string]interface{})
+
+	if last["action_name"] != "baz" || last["status"] != "FAILED" {
+		t.Fatalf("Expected most recent events to be kept, got %v", got)
+	}
+}